@@ -0,0 +1,365 @@
+package googleworkspace
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+const (
+	// The format of the start and end times of the activities to list
+	activityTimeFormat = "2006-01-02T15:04:05.000Z"
+
+	// The window of the activities listed when a query has no qual on the time column, unless configured otherwise
+	defaultActivityWindow = 24 * time.Hour
+)
+
+// activityEvent is an event of an activity, with its parameters normalized into plain JSON values.
+// The activity is embedded by value, since the transforms of the columns don't follow embedded pointers.
+type activityEvent struct {
+	Activity
+	Event      *ActivityEvents
+	EventIndex int
+	Parameters map[string]interface{}
+}
+
+// newActivitiesListCall returns the call listing the activities of the given application which
+// match the quals of the query, for the tables based on the Reports API's activities, or nil if
+// the quals on the time column can't match any activity.
+func newActivitiesListCall(ctx context.Context, d *plugin.QueryData, applicationName string) (*ActivitiesListCall, error) {
+	// Create service
+	service, err := AdminReportsService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	userKey := "all"
+	if d.KeyColumnQuals["user_key"] != nil {
+		userKey = d.KeyColumnQuals["user_key"].GetStringValue()
+	}
+
+	// Setting the maximum number of activities, API can return in a single page
+	maxResults := int64(1000)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResults {
+			maxResults = *limit
+		}
+	}
+	call := service.Activities.List(userKey, applicationName).MaxResults(maxResults)
+
+	if d.KeyColumnQuals["actor_ip_address"] != nil {
+		call.ActorIpAddress(d.KeyColumnQuals["actor_ip_address"].GetStringValue())
+	}
+	if d.KeyColumnQuals["customer_id"] != nil {
+		call.CustomerId(d.KeyColumnQuals["customer_id"].GetStringValue())
+	}
+	if d.KeyColumnQuals["event_name"] != nil {
+		call.EventName(d.KeyColumnQuals["event_name"].GetStringValue())
+	}
+	if d.KeyColumnQuals["filters"] != nil {
+		call.Filters(d.KeyColumnQuals["filters"].GetStringValue())
+	}
+	if d.KeyColumnQuals["org_unit_id"] != nil {
+		call.OrgUnitID(d.KeyColumnQuals["org_unit_id"].GetStringValue())
+	}
+	if d.KeyColumnQuals["group_id_filter"] != nil {
+		call.GroupIdFilter(d.KeyColumnQuals["group_id_filter"].GetStringValue())
+	}
+
+	start, end, err := activityTimeRange(d)
+	if err != nil {
+		return nil, err
+	}
+	if !start.IsZero() && !end.IsZero() && start.After(end) {
+		return nil, nil
+	}
+	if !start.IsZero() {
+		call.StartTime(start.Format(activityTimeFormat))
+	}
+	if !end.IsZero() {
+		call.EndTime(end.Format(activityTimeFormat))
+	}
+
+	return call, nil
+}
+
+// activityTimeRange returns the range of times of the activities matching the quals on the time column.
+// The API's start and end times are both inclusive, with a precision of a millisecond, the precision of
+// the times of the activities. A zero start or end time leaves the range open on that side. Without any
+// qual on the time column, the range is the default activity window of the connection, up to now.
+func activityTimeRange(d *plugin.QueryData) (time.Time, time.Time, error) {
+	var start, end time.Time
+
+	if d.Quals["time"] == nil {
+		window, err := activityDefaultWindow(d)
+		if err != nil {
+			return start, end, err
+		}
+		if window > 0 {
+			start = time.Now().UTC().Add(-window)
+		}
+		return start, end, nil
+	}
+
+	for _, q := range d.Quals["time"].Quals {
+		givenTime, err := activityQualTime(q.Value)
+		if err != nil {
+			return start, end, err
+		}
+
+		// The earliest and latest activity times matching the qual, to the millisecond
+		floor := givenTime.Truncate(time.Millisecond)
+		ceiling := floor
+		if ceiling.Before(givenTime) {
+			ceiling = ceiling.Add(time.Millisecond)
+		}
+
+		var from, to time.Time
+		switch q.Operator {
+		case ">":
+			from = floor.Add(time.Millisecond)
+		case ">=":
+			from = ceiling
+		case "=":
+			from, to = ceiling, floor
+		case "<=":
+			to = floor
+		case "<":
+			to = ceiling.Add(-time.Millisecond)
+		}
+
+		// Several quals on the time column narrow the range down
+		if !from.IsZero() && from.After(start) {
+			start = from
+		}
+		if !to.IsZero() && (end.IsZero() || to.Before(end)) {
+			end = to
+		}
+	}
+
+	return start, end, nil
+}
+
+// Returns the time of a qual on the time column, in UTC
+func activityQualTime(value *proto.QualValue) (time.Time, error) {
+	if timestamp := value.GetTimestampValue(); timestamp != nil {
+		return timestamp.AsTime().UTC(), nil
+	}
+
+	// Timestamps given as strings keep their offset, if any
+	givenTime, err := time.Parse(time.RFC3339Nano, value.GetStringValue())
+	if err != nil {
+		return givenTime, fmt.Errorf("time must be an RFC 3339 timestamp, e.g. 2022-02-01T15:04:05+01:00: %v", err)
+	}
+	return givenTime.UTC(), nil
+}
+
+// Returns the window of the activities listed when a query has no qual on the time column
+func activityDefaultWindow(d *plugin.QueryData) (time.Duration, error) {
+	googleworkspaceConfig := GetConfig(d.Connection)
+	if googleworkspaceConfig.ActivityDefaultWindow == nil {
+		return defaultActivityWindow, nil
+	}
+
+	window := strings.TrimSpace(*googleworkspaceConfig.ActivityDefaultWindow)
+	if window == "0" || window == "" {
+		return 0, nil
+	}
+
+	// Durations can also be given in days, which time.ParseDuration doesn't support
+	if days := strings.TrimSuffix(window, "d"); days != window {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("activity_default_window must be a duration, e.g. 24h or 7d, got %q", window)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("activity_default_window must be a duration, e.g. 24h or 7d, got %q", window)
+	}
+	return duration, nil
+}
+
+// activityParameterFilters returns the filters on the event parameters matching the quals of the query
+// on the given columns, which are named after the parameters, e.g. doc_id==12345. The filters of a query
+// are combined with AND. Values containing a comma can't be filtered on, since the comma separates the
+// filters; the other quals still filter the events.
+func activityParameterFilters(d *plugin.QueryData, columns []string) string {
+	var filters []string
+	for _, column := range columns {
+		if d.Quals[column] == nil {
+			continue
+		}
+		for _, q := range d.Quals[column].Quals {
+			value := q.Value.GetStringValue()
+			if strings.Contains(value, ",") {
+				continue
+			}
+
+			switch q.Operator {
+			case "=":
+				filters = append(filters, fmt.Sprintf("%s==%s", column, value))
+			case "!=", "<>":
+				filters = append(filters, fmt.Sprintf("%s<>%s", column, value))
+			}
+		}
+	}
+	return strings.Join(filters, ",")
+}
+
+// listActivityEvents streams an activityEvent for every event of the activities listed by the given
+// call. If the query has an event_name qual, only the events with that name are streamed, since the
+// activities the API returns for an event name may have other events too.
+func listActivityEvents(ctx context.Context, d *plugin.QueryData, call *ActivitiesListCall) error {
+	var eventName string
+	if d.KeyColumnQuals["event_name"] != nil {
+		eventName = d.KeyColumnQuals["event_name"].GetStringValue()
+	}
+
+	return call.Pages(ctx, func(page *Activities) error {
+		for _, activity := range page.Items {
+			for i, event := range activity.Events {
+				if eventName != "" && event.Name != eventName {
+					continue
+				}
+				d.StreamListItem(ctx, activityEvent{
+					Activity:   *activity,
+					Event:      event,
+					EventIndex: i,
+					Parameters: activityEventParameters(event.Parameters),
+				})
+
+				// Context can be cancelled due to manual cancellation or the limit has been hit
+				if plugin.IsCancelled(ctx) {
+					page.NextPageToken = ""
+					return nil
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// activityEventParameters returns the parameters of an event by name. The value of each parameter
+// is held in the field of ActivityEventsParameters matching its type; it is normalized into a plain
+// JSON value: value is a string, intValue a number, boolValue a boolean, multiValue, multiIntValue
+// and multiBoolValue arrays, and messageValue an object of its own parameters by name.
+func activityEventParameters(parameters []map[string]interface{}) map[string]interface{} {
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	values := map[string]interface{}{}
+	for _, parameter := range parameters {
+		name, ok := parameter["name"].(string)
+		if !ok {
+			continue
+		}
+		values[name] = activityParameterValue(parameter)
+	}
+	return values
+}
+
+// Returns the normalized value of a parameter, or of a nested parameter of a message value
+func activityParameterValue(parameter map[string]interface{}) interface{} {
+	if value, ok := parameter["value"]; ok {
+		return value
+	}
+	if value, ok := parameter["intValue"]; ok {
+		return activityIntValue(value)
+	}
+	if value, ok := parameter["boolValue"]; ok {
+		return value
+	}
+	if value, ok := parameter["multiValue"]; ok {
+		return value
+	}
+	if value, ok := parameter["multiIntValue"].([]interface{}); ok {
+		ints := make([]interface{}, len(value))
+		for i, item := range value {
+			ints[i] = activityIntValue(item)
+		}
+		return ints
+	}
+	if value, ok := parameter["multiBoolValue"]; ok {
+		return value
+	}
+	if value, ok := parameter["messageValue"].(map[string]interface{}); ok {
+		return activityMessageValue(value)
+	}
+	if value, ok := parameter["multiMessageValue"].([]interface{}); ok {
+		messages := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			if message, ok := item.(map[string]interface{}); ok {
+				messages = append(messages, activityMessageValue(message))
+			}
+		}
+		return messages
+	}
+	return nil
+}
+
+// Returns the nested parameters of a message value by name
+func activityMessageValue(message map[string]interface{}) map[string]interface{} {
+	nested, _ := message["parameter"].([]interface{})
+
+	parameters := make([]map[string]interface{}, 0, len(nested))
+	for _, item := range nested {
+		if parameter, ok := item.(map[string]interface{}); ok {
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	values := activityEventParameters(parameters)
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values
+}
+
+// Integers are encoded as strings by the API, to preserve their precision
+func activityIntValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case float64:
+		return int64(v)
+	}
+	return value
+}
+
+//// TRANSFORM FUNCTIONS
+
+// activityUserKey returns the user_key of an activity, from its actor. The user key given in the
+// where clause is pushed down to the API, which accepts the email address or the profile ID of a
+// user, so it is returned as is, for the rows to match the qual. Otherwise, the actor's email
+// address is returned, or its key if the actor isn't a user.
+func activityUserKey(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	if quals := d.KeyColumnQuals["user_key"]; len(quals) > 0 {
+		return quals[0].Value.GetStringValue(), nil
+	}
+
+	actor, ok := d.Value.(*ActivityActor)
+	if !ok || actor == nil {
+		return nil, nil
+	}
+	if actor.Email != "" {
+		return actor.Email, nil
+	}
+	if actor.Key != "" {
+		return actor.Key, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,204 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+// usageReportsCall is a call getting a usage report, page by page
+type usageReportsCall interface {
+	Pages(ctx context.Context, f func(*UsageReports) error) error
+}
+
+// usageReportParameter is a parameter of a usage report, whose value is held in the field matching its type.
+// The value and its type are also held whatever the type, for the parameters to be queried alike.
+// The report is embedded by value, since the transforms of the columns don't follow embedded pointers.
+type usageReportParameter struct {
+	UsageReport
+	Name          string
+	ValueType     string
+	Value         interface{}
+	IntValue      interface{}
+	BoolValue     interface{}
+	DatetimeValue interface{}
+	StringValue   interface{}
+	MsgValue      interface{}
+}
+
+// The types of the values of usage report parameters, by the field of UsageReportParameters holding them
+var usageReportValueTypes = []struct {
+	field     string
+	valueType string
+}{
+	{"intValue", "int"},
+	{"boolValue", "bool"},
+	{"datetimeValue", "datetime"},
+	{"stringValue", "string"},
+	{"msgValue", "msg"},
+}
+
+// newUsageReportParameter returns the parameter of the given usage report with the given raw value, which holds
+// the fields of UsageReportParameters. Only the field matching the type of the parameter is set.
+func newUsageReportParameter(report UsageReport, parameter map[string]interface{}) usageReportParameter {
+	name, _ := parameter["name"].(string)
+	row := usageReportParameter{
+		UsageReport:   report,
+		Name:          name,
+		BoolValue:     parameter["boolValue"],
+		DatetimeValue: parameter["datetimeValue"],
+		StringValue:   parameter["stringValue"],
+		MsgValue:      parameter["msgValue"],
+	}
+	if value, ok := parameter["intValue"]; ok {
+		row.IntValue = activityIntValue(value)
+	}
+
+	for _, t := range usageReportValueTypes {
+		if _, ok := parameter[t.field]; ok {
+			row.ValueType = t.valueType
+			break
+		}
+	}
+	switch row.ValueType {
+	case "int":
+		row.Value = row.IntValue
+	case "bool":
+		row.Value = row.BoolValue
+	case "datetime":
+		row.Value = row.DatetimeValue
+	case "string":
+		row.Value = row.StringValue
+	case "msg":
+		row.Value = row.MsgValue
+	}
+	return row
+}
+
+// Returns the parameters to get the usage of: the parameters qual, or else the parameter_name qual of the
+// tables with one row per parameter, e.g. gmail:num_emails_sent or accounts:drive_used_quota_in_mb
+func usageReportParametersQual(d *plugin.QueryData) string {
+	if d.KeyColumnQuals["parameters"] != nil {
+		return d.KeyColumnQuals["parameters"].GetStringValue()
+	}
+	if d.KeyColumnQuals["parameter_name"] != nil {
+		return d.KeyColumnQuals["parameter_name"].GetStringValue()
+	}
+	return ""
+}
+
+// listUsageReportParameters streams a usageReportParameter for every parameter of the usage reports got by the
+// given call. If the query has a parameter_name qual, only the parameters with that name are streamed.
+func listUsageReportParameters(ctx context.Context, d *plugin.QueryData, call usageReportsCall) error {
+	var parameterName string
+	if d.KeyColumnQuals["parameter_name"] != nil {
+		parameterName = d.KeyColumnQuals["parameter_name"].GetStringValue()
+	}
+
+	return call.Pages(ctx, func(page *UsageReports) error {
+		for _, report := range page.UsageReports {
+			for _, parameter := range report.Parameters {
+				row := newUsageReportParameter(*report, parameter)
+				if parameterName != "" && row.Name != parameterName {
+					continue
+				}
+				d.StreamListItem(ctx, row)
+
+				// Context can be cancelled due to manual cancellation or the limit has been hit
+				if plugin.IsCancelled(ctx) {
+					page.NextPageToken = ""
+					return nil
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// usageReportParameterTable returns the table listing the parameters of the usage reports of the given
+// table, one row per entity, date and parameter, with the same key columns and the same entity columns.
+func usageReportParameterTable(reportTable *plugin.Table, description string, hydrate plugin.HydrateFunc) *plugin.Table {
+	keyColumns := plugin.KeyColumnSlice{}
+	for _, keyColumn := range reportTable.List.KeyColumns {
+		copied := *keyColumn
+		keyColumns = append(keyColumns, &copied)
+	}
+	keyColumns = append(keyColumns, &plugin.KeyColumn{
+		Name:    "parameter_name",
+		Require: plugin.Optional,
+	})
+
+	// The parameters of the report are replaced by the parameter of the row
+	columns := []*plugin.Column{}
+	for _, column := range reportTable.Columns {
+		copied := *column
+		if copied.Name == "parameters" {
+			copied.Description = "Comma-separated list of the parameters to get the usage of, e.g. accounts:drive_used_quota_in_mb,gmail:num_emails_sent"
+			copied.Type = proto.ColumnType_STRING
+			copied.Transform = transform.FromQual("parameters")
+		}
+		columns = append(columns, &copied)
+	}
+	columns = append(columns,
+		&plugin.Column{
+			Name:        "parameter_name",
+			Description: "The name of the parameter, prefixed with its application, e.g. accounts:drive_used_quota_in_mb.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("Name"),
+		},
+		&plugin.Column{
+			Name:        "value_type",
+			Description: "The type of the value of the parameter, i.e. the column holding it. Possible values are: int, bool, datetime, string and msg.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("ValueType").NullIfZero(),
+		},
+		&plugin.Column{
+			Name:        "value",
+			Description: "The value of the parameter, whatever its type.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("Value"),
+		},
+		&plugin.Column{
+			Name:        "int_value",
+			Description: "The value of the parameter, if it is an integer, e.g. a count or a size.",
+			Type:        proto.ColumnType_INT,
+			Transform:   transform.FromField("IntValue"),
+		},
+		&plugin.Column{
+			Name:        "bool_value",
+			Description: "The value of the parameter, if it is a boolean.",
+			Type:        proto.ColumnType_BOOL,
+			Transform:   transform.FromField("BoolValue"),
+		},
+		&plugin.Column{
+			Name:        "datetime_value",
+			Description: "The value of the parameter, if it is a time, e.g. the time of the last login.",
+			Type:        proto.ColumnType_TIMESTAMP,
+			Transform:   transform.FromField("DatetimeValue"),
+		},
+		&plugin.Column{
+			Name:        "string_value",
+			Description: "The value of the parameter, if it is a string.",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField("StringValue"),
+		},
+		&plugin.Column{
+			Name:        "msg_value",
+			Description: "The value of the parameter, if it is a list of objects, e.g. the counts of each kind of device.",
+			Type:        proto.ColumnType_JSON,
+			Transform:   transform.FromField("MsgValue"),
+		},
+	)
+
+	return &plugin.Table{
+		Name:        reportTable.Name + "_parameter",
+		Description: description,
+		List: &plugin.ListConfig{
+			Hydrate:    hydrate,
+			KeyColumns: keyColumns,
+		},
+		Columns: columns,
+	}
+}
@@ -0,0 +1,301 @@
+package googleworkspace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiUsageKey identifies the calls whose usage is accounted together: the calls of a connection
+// to a method of an API, e.g. users.list of the directory API, made by the queries of a table
+type apiUsageKey struct {
+	Connection string
+	API        string
+	Table      string
+	APIMethod  string
+}
+
+// apiUsage is the usage of the APIs by the plugin since the process started
+type apiUsage struct {
+	apiUsageKey
+
+	CallCount        int64
+	ErrorCount       int64
+	RateLimitedCount int64
+	RetryCount       int64
+	BytesSent        int64
+	BytesReceived    int64
+	TotalLatency     time.Duration
+	MaxLatency       time.Duration
+	FirstCallTime    time.Time
+	LastCallTime     time.Time
+
+	// Set in the snapshots returned by apiUsageOfConnection
+	AverageLatencyMs float64
+	MaxLatencyMs     float64
+}
+
+// The usage of every connection, API, table and API method, for the lifetime of the process
+var apiUsageRegistry = struct {
+	sync.Mutex
+	usage map[apiUsageKey]*apiUsage
+}{usage: map[apiUsageKey]*apiUsage{}}
+
+// recordAPIUsage applies the given update to the usage with the given key
+func recordAPIUsage(key apiUsageKey, update func(usage *apiUsage)) {
+	apiUsageRegistry.Lock()
+	defer apiUsageRegistry.Unlock()
+
+	usage, ok := apiUsageRegistry.usage[key]
+	if !ok {
+		usage = &apiUsage{apiUsageKey: key}
+		apiUsageRegistry.usage[key] = usage
+	}
+	update(usage)
+}
+
+// apiUsageOfConnection returns a snapshot of the usage of the given connection, sorted by API, table and API method
+func apiUsageOfConnection(connection string) []apiUsage {
+	apiUsageRegistry.Lock()
+	defer apiUsageRegistry.Unlock()
+
+	var snapshot []apiUsage
+	for key, usage := range apiUsageRegistry.usage {
+		if key.Connection != connection {
+			continue
+		}
+		row := *usage
+		if row.CallCount > 0 {
+			row.AverageLatencyMs = float64(row.TotalLatency) / float64(row.CallCount) / float64(time.Millisecond)
+		}
+		row.MaxLatencyMs = float64(row.MaxLatency) / float64(time.Millisecond)
+		snapshot = append(snapshot, row)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		a, b := snapshot[i].apiUsageKey, snapshot[j].apiUsageKey
+		if a.API != b.API {
+			return a.API < b.API
+		}
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		return a.APIMethod < b.APIMethod
+	})
+	return snapshot
+}
+
+// The context key of the retry attempt a request is sent for, set by retryTransport
+type retryAttemptKey struct{}
+
+// Returns the retry attempt the request of the given context is sent for; 0 for the first attempt
+func retryAttempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptKey{}).(int)
+	return attempt
+}
+
+// usageTransport accounts every HTTP call of the services of a table to an API
+type usageTransport struct {
+	base       http.RoundTripper
+	connection string
+	api        string
+	table      string
+
+	// The path of the endpoint the requests are sent to
+	basePath string
+}
+
+func (t *usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := apiUsageKey{t.connection, t.api, t.table, apiMethod(req, t.api, t.basePath)}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	recordAPIUsage(key, func(usage *apiUsage) {
+		usage.CallCount++
+		if retryAttempt(req.Context()) > 0 {
+			usage.RetryCount++
+		}
+		if req.ContentLength > 0 {
+			usage.BytesSent += req.ContentLength
+		}
+		if err != nil || resp.StatusCode >= 400 {
+			usage.ErrorCount++
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			usage.RateLimitedCount++
+		}
+		usage.TotalLatency += latency
+		if latency > usage.MaxLatency {
+			usage.MaxLatency = latency
+		}
+		if usage.FirstCallTime.IsZero() {
+			usage.FirstCallTime = start
+		}
+		usage.LastCallTime = start
+	})
+
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingReadCloser{ReadCloser: resp.Body, key: key}
+	}
+	return resp, err
+}
+
+// countingReadCloser accounts the bytes of a response body as they are read
+type countingReadCloser struct {
+	io.ReadCloser
+	key apiUsageKey
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		recordAPIUsage(r.key, func(usage *apiUsage) {
+			usage.BytesReceived += int64(n)
+		})
+	}
+	return n, err
+}
+
+// The default endpoints of the APIs, which the paths of their methods are relative to
+var apiDefaultEndpoints = map[string]string{
+	"admin_reports": "https://admin.googleapis.com/",
+	"calendar":      "https://www.googleapis.com/calendar/v3/",
+	"directory":     "https://admin.googleapis.com/",
+	"drive":         "https://www.googleapis.com/drive/v3/",
+	"gmail":         "https://gmail.googleapis.com/",
+	"people":        "https://people.googleapis.com/",
+}
+
+// apiMethodPath is the path of an API method, e.g. "GET admin/directory/v1/users/{}" for users.get
+type apiMethodPath struct {
+	method string
+	path   string
+}
+
+// The paths of the API methods called by the plugin's tables, by API, with the ID of the method in
+// the API's discovery document. The paths are relative to the endpoint of the API, which is the
+// configured one if any, so the version path of the APIs whose default endpoint includes it is left
+// out. A {} segment matches any one path segment, and a trailing {+} matches the rest of the path.
+// Patterns are tried in order, so the more specific ones come first.
+var apiMethodPaths = map[string][]apiMethodPath{
+	"admin_reports": {
+		{"activities.list", "GET admin/reports/v1/activity/users/{}/applications/{}"},
+		{"customerUsageReports.get", "GET admin/reports/v1/usage/dates/{}"},
+		{"userUsageReport.get", "GET admin/reports/v1/usage/users/{}/dates/{}"},
+		{"entityUsageReports.get", "GET admin/reports/v1/usage/{}/{}/dates/{}"},
+	},
+	"directory": {
+		{"chromeosdevices.list", "GET admin/directory/v1/customer/{}/devices/chromeos"},
+		{"chromeosdevices.get", "GET admin/directory/v1/customer/{}/devices/chromeos/{}"},
+		{"mobiledevices.list", "GET admin/directory/v1/customer/{}/devices/mobile"},
+		{"mobiledevices.get", "GET admin/directory/v1/customer/{}/devices/mobile/{}"},
+		{"domainAliases.list", "GET admin/directory/v1/customer/{}/domainaliases"},
+		{"domainAliases.get", "GET admin/directory/v1/customer/{}/domainaliases/{}"},
+		{"domains.list", "GET admin/directory/v1/customer/{}/domains"},
+		{"domains.get", "GET admin/directory/v1/customer/{}/domains/{}"},
+		{"orgunits.list", "GET admin/directory/v1/customer/{}/orgunits"},
+		{"orgunits.get", "GET admin/directory/v1/customer/{}/orgunits/{+}"},
+		{"resources.buildings.list", "GET admin/directory/v1/customer/{}/resources/buildings"},
+		{"resources.buildings.get", "GET admin/directory/v1/customer/{}/resources/buildings/{}"},
+		{"resources.calendars.list", "GET admin/directory/v1/customer/{}/resources/calendars"},
+		{"resources.calendars.get", "GET admin/directory/v1/customer/{}/resources/calendars/{}"},
+		{"resources.features.list", "GET admin/directory/v1/customer/{}/resources/features"},
+		{"resources.features.get", "GET admin/directory/v1/customer/{}/resources/features/{}"},
+		{"roleAssignments.list", "GET admin/directory/v1/customer/{}/roleassignments"},
+		{"roleAssignments.get", "GET admin/directory/v1/customer/{}/roleassignments/{}"},
+		{"privileges.list", "GET admin/directory/v1/customer/{}/roles/ALL/privileges"},
+		{"roles.list", "GET admin/directory/v1/customer/{}/roles"},
+		{"roles.get", "GET admin/directory/v1/customer/{}/roles/{}"},
+		{"schemas.list", "GET admin/directory/v1/customer/{}/schemas"},
+		{"schemas.get", "GET admin/directory/v1/customer/{}/schemas/{}"},
+		{"customers.get", "GET admin/directory/v1/customers/{}"},
+		{"groups.list", "GET admin/directory/v1/groups"},
+		{"groups.get", "GET admin/directory/v1/groups/{}"},
+		{"members.list", "GET admin/directory/v1/groups/{}/members"},
+		{"users.list", "GET admin/directory/v1/users"},
+		{"users.get", "GET admin/directory/v1/users/{}"},
+		{"tokens.list", "GET admin/directory/v1/users/{}/tokens"},
+	},
+	"calendar": {
+		{"calendars.get", "GET calendars/{}"},
+		{"events.list", "GET calendars/{}/events"},
+		{"events.get", "GET calendars/{}/events/{}"},
+	},
+	"drive": {
+		{"drives.list", "GET drives"},
+		{"drives.get", "GET drives/{}"},
+		{"files.list", "GET files"},
+		{"files.get", "GET files/{}"},
+	},
+	"gmail": {
+		{"users.getProfile", "GET gmail/v1/users/{}/profile"},
+		{"users.drafts.list", "GET gmail/v1/users/{}/drafts"},
+		{"users.drafts.get", "GET gmail/v1/users/{}/drafts/{}"},
+		{"users.messages.list", "GET gmail/v1/users/{}/messages"},
+		{"users.messages.get", "GET gmail/v1/users/{}/messages/{}"},
+		{"users.settings.getAutoForwarding", "GET gmail/v1/users/{}/settings/autoForwarding"},
+		{"users.settings.getImap", "GET gmail/v1/users/{}/settings/imap"},
+		{"users.settings.getLanguage", "GET gmail/v1/users/{}/settings/language"},
+		{"users.settings.getPop", "GET gmail/v1/users/{}/settings/pop"},
+		{"users.settings.getVacation", "GET gmail/v1/users/{}/settings/vacation"},
+		{"users.settings.delegates.list", "GET gmail/v1/users/{}/settings/delegates"},
+	},
+	"people": {
+		{"contactGroups.list", "GET v1/contactGroups"},
+		{"people.listDirectoryPeople", "GET v1/people:listDirectoryPeople"},
+		{"people.connections.list", "GET v1/people/{}/connections"},
+	},
+}
+
+// apiMethod returns the ID of the method of the given API the given request calls, e.g. users.list,
+// given the path of the endpoint the request is sent to. The requests of methods the plugin doesn't
+// call are accounted under their HTTP method instead.
+func apiMethod(req *http.Request, api string, basePath string) string {
+	if !strings.HasPrefix(req.URL.Path, basePath) {
+		return req.Method
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, basePath), "/"), "/")
+	for _, p := range apiMethodPaths[api] {
+		fields := strings.Fields(p.path)
+		if fields[0] == req.Method && matchPathSegments(strings.Split(fields[1], "/"), segments) {
+			return p.method
+		}
+	}
+	return req.Method
+}
+
+// apiBasePath returns the path of the endpoint the requests of the given API are sent to, i.e. the
+// configured endpoint, if any, or else the default endpoint of the API, with a trailing slash
+func apiBasePath(googleworkspaceConfig googleworkspaceConfig, api string) string {
+	endpoint := apiEndpoint(googleworkspaceConfig, api)
+	if endpoint == "" {
+		endpoint = apiDefaultEndpoints[api]
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "/"
+	}
+	return strings.TrimSuffix(u.Path, "/") + "/"
+}
+
+// Returns true if the segments of a path match the given pattern
+func matchPathSegments(pattern []string, segments []string) bool {
+	for i, p := range pattern {
+		if p == "{+}" {
+			return i < len(segments)
+		}
+		if i >= len(segments) || (p != "{}" && p != segments[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
@@ -0,0 +1,117 @@
+package googleworkspace
+
+import (
+	"container/list"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// The maximum number of token sources and services kept in memory
+	clientCacheMaxSize = 1000
+
+	// Token sources and services not used for this long are evicted
+	clientCacheIdleTTL = 1 * time.Hour
+)
+
+// Token sources, services and rate limiters are shared by all the queries of the plugin process
+var clients = newClientCache(clientCacheMaxSize, clientCacheIdleTTL)
+
+// clientCache is a least-recently-used cache of token sources and services. Since the plugin
+// may impersonate every user in the domain, the cache is bounded in size, and entries which
+// haven't been used for a while are evicted.
+type clientCache struct {
+	mu      sync.Mutex
+	maxSize int
+	idleTTL time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type clientCacheEntry struct {
+	key      string
+	value    interface{}
+	lastUsed time.Time
+}
+
+func newClientCache(maxSize int, idleTTL time.Duration) *clientCache {
+	return &clientCache{
+		maxSize: maxSize,
+		idleTTL: idleTTL,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// getOrCreate returns the value cached for the key, or calls create and caches its result.
+// The lock isn't held while create runs, since creating a service may itself get a token
+// source from the cache; if concurrent calls create the same value, the first one cached wins.
+func (c *clientCache) getOrCreate(key string, create func() (interface{}, error)) (interface{}, error) {
+	if value, ok := c.get(key); ok {
+		return value, nil
+	}
+
+	value, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		return element.Value.(*clientCacheEntry).value, nil
+	}
+
+	c.entries[key] = c.order.PushFront(&clientCacheEntry{key: key, value: value, lastUsed: time.Now()})
+	for c.order.Len() > c.maxSize {
+		c.remove(c.order.Back())
+	}
+
+	return value, nil
+}
+
+func (c *clientCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.evictIdle(now)
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*clientCacheEntry)
+	entry.lastUsed = now
+	c.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+// evictIdle removes the entries which haven't been used since the idle TTL. The least
+// recently used entries are at the back of the list, so eviction stops at the first live entry.
+func (c *clientCache) evictIdle(now time.Time) {
+	for element := c.order.Back(); element != nil; element = c.order.Back() {
+		if now.Sub(element.Value.(*clientCacheEntry).lastUsed) < c.idleTTL {
+			return
+		}
+		c.remove(element)
+	}
+}
+
+func (c *clientCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*clientCacheEntry).key)
+}
+
+// clientCacheKey builds the cache key of a token source or service. The scopes are sorted,
+// so the same scope set always maps to the same key.
+func clientCacheKey(kind string, connectionName string, subject string, scopes []string) string {
+	sorted := append([]string{}, scopes...)
+	sort.Strings(sorted)
+	return strings.Join([]string{kind, connectionName, subject, strings.Join(sorted, ",")}, "|")
+}
@@ -0,0 +1,92 @@
+package googleworkspace
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Returns a create function for getOrCreate which returns the given value, and counts its calls
+func countingCreate(value interface{}, calls *int) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		*calls++
+		return value, nil
+	}
+}
+
+func TestClientCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newClientCache(2, time.Hour)
+
+	var calls int
+	cache.getOrCreate("a", countingCreate("a", &calls))
+	cache.getOrCreate("b", countingCreate("b", &calls))
+
+	// Using a makes b the least recently used entry, which is evicted when c is added
+	cache.getOrCreate("a", countingCreate("a", &calls))
+	cache.getOrCreate("c", countingCreate("c", &calls))
+	if calls != 3 {
+		t.Fatalf("created %d values, want 3", calls)
+	}
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("b is still cached after exceeding the capacity")
+	}
+	for _, key := range []string{"a", "c"} {
+		if value, ok := cache.get(key); !ok || value != key {
+			t.Errorf("%s = %v, %t, want %s, true", key, value, ok, key)
+		}
+	}
+}
+
+func TestClientCacheEvictsIdleEntries(t *testing.T) {
+	cache := newClientCache(10, 20*time.Millisecond)
+
+	var calls int
+	cache.getOrCreate("a", countingCreate("a", &calls))
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := cache.get("a"); ok {
+		t.Error("a is still cached after the idle TTL")
+	}
+	cache.getOrCreate("a", countingCreate("a", &calls))
+	if calls != 2 {
+		t.Errorf("created %d values, want 2", calls)
+	}
+}
+
+func TestClientCacheConcurrentGetOrCreate(t *testing.T) {
+	cache := newClientCache(10, time.Hour)
+
+	// Every caller must get the same value, even if several of them created one
+	const callers = 50
+	values := make([]interface{}, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value, err := cache.getOrCreate("key", func() (interface{}, error) {
+				return new(int), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			values[i] = value
+		}(i)
+	}
+	wg.Wait()
+
+	for i, value := range values {
+		if value != values[0] {
+			t.Fatalf("caller %d got %p, want %p", i, value, values[0])
+		}
+	}
+}
+
+func TestClientCacheKeyIgnoresScopeOrder(t *testing.T) {
+	a := clientCacheKey("kind", "connection", "user@example.com", []string{"scope-b", "scope-a"})
+	b := clientCacheKey("kind", "connection", "user@example.com", []string{"scope-a", "scope-b"})
+	if a != b {
+		t.Errorf("keys %q and %q differ", a, b)
+	}
+}
@@ -6,10 +6,26 @@ import (
 )
 
 type googleworkspaceConfig struct {
-	CredentialFile        *string `cty:"credential_file"`
-	Credentials           *string `cty:"credentials"`
-	ImpersonatedUserEmail *string `cty:"impersonated_user_email"`
-	TokenPath             *string `cty:"token_path"`
+	CredentialFile            *string  `cty:"credential_file"`
+	Credentials               *string  `cty:"credentials"`
+	ImpersonatedUserEmail     *string  `cty:"impersonated_user_email"`
+	ImpersonateServiceAccount *string  `cty:"impersonate_service_account"`
+	TokenPath                 *string  `cty:"token_path"`
+	EnableUserFanOut          *bool    `cty:"enable_user_fan_out"`
+	UserFanOutConcurrency     *int     `cty:"user_fan_out_concurrency"`
+	UserCustomSchemaFields    []string `cty:"user_custom_schema_fields"`
+	AdminReportsEndpoint      *string  `cty:"admin_reports_endpoint"`
+	DirectoryEndpoint         *string  `cty:"directory_endpoint"`
+	GmailEndpoint             *string  `cty:"gmail_endpoint"`
+	CalendarEndpoint          *string  `cty:"calendar_endpoint"`
+	DriveEndpoint             *string  `cty:"drive_endpoint"`
+	PeopleEndpoint            *string  `cty:"people_endpoint"`
+	ProxyURL                  *string  `cty:"proxy_url"`
+	CABundle                  *string  `cty:"ca_bundle"`
+	MaxRetries                *int     `cty:"max_retries"`
+	APIRequestsPerSecond      *int     `cty:"api_requests_per_second"`
+	UserRequestsPerSecond     *int     `cty:"user_requests_per_second"`
+	ActivityDefaultWindow     *string  `cty:"activity_default_window"`
 }
 
 var ConfigSchema = map[string]*schema.Attribute{
@@ -22,9 +38,58 @@ var ConfigSchema = map[string]*schema.Attribute{
 	"impersonated_user_email": {
 		Type: schema.TypeString,
 	},
+	"impersonate_service_account": {
+		Type: schema.TypeString,
+	},
 	"token_path": {
 		Type: schema.TypeString,
 	},
+	"enable_user_fan_out": {
+		Type: schema.TypeBool,
+	},
+	"user_fan_out_concurrency": {
+		Type: schema.TypeInt,
+	},
+	"user_custom_schema_fields": {
+		Type: schema.TypeList,
+		Elem: &schema.Attribute{Type: schema.TypeString},
+	},
+	"admin_reports_endpoint": {
+		Type: schema.TypeString,
+	},
+	"directory_endpoint": {
+		Type: schema.TypeString,
+	},
+	"gmail_endpoint": {
+		Type: schema.TypeString,
+	},
+	"calendar_endpoint": {
+		Type: schema.TypeString,
+	},
+	"drive_endpoint": {
+		Type: schema.TypeString,
+	},
+	"people_endpoint": {
+		Type: schema.TypeString,
+	},
+	"proxy_url": {
+		Type: schema.TypeString,
+	},
+	"ca_bundle": {
+		Type: schema.TypeString,
+	},
+	"max_retries": {
+		Type: schema.TypeInt,
+	},
+	"api_requests_per_second": {
+		Type: schema.TypeInt,
+	},
+	"user_requests_per_second": {
+		Type: schema.TypeInt,
+	},
+	"activity_default_window": {
+		Type: schema.TypeString,
+	},
 }
 
 func ConfigInstance() interface{} {
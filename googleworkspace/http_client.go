@@ -0,0 +1,125 @@
+package googleworkspace
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+// Returns the base URL configured to replace the default endpoint of the given API, if any,
+// e.g. to send the requests to a local stand-in of the API
+func apiEndpoint(googleworkspaceConfig googleworkspaceConfig, api string) string {
+	var endpoint *string
+	switch api {
+	case "admin_reports":
+		endpoint = googleworkspaceConfig.AdminReportsEndpoint
+	case "calendar":
+		endpoint = googleworkspaceConfig.CalendarEndpoint
+	case "directory":
+		endpoint = googleworkspaceConfig.DirectoryEndpoint
+	case "drive":
+		endpoint = googleworkspaceConfig.DriveEndpoint
+	case "gmail":
+		endpoint = googleworkspaceConfig.GmailEndpoint
+	case "people":
+		endpoint = googleworkspaceConfig.PeopleEndpoint
+	}
+	if endpoint == nil {
+		return ""
+	}
+	return *endpoint
+}
+
+// Returns the HTTP client which sends the requests of the connection, both to the APIs and to
+// fetch tokens, through the configured proxy and trusting the configured CA bundle. Returns nil
+// if neither is configured, in which case the default client of the client libraries is used.
+func getBaseHTTPClient(d *plugin.QueryData) (*http.Client, error) {
+	googleworkspaceConfig := GetConfig(d.Connection)
+
+	var proxyURL, caBundle string
+	if googleworkspaceConfig.ProxyURL != nil {
+		proxyURL = *googleworkspaceConfig.ProxyURL
+	}
+	if googleworkspaceConfig.CABundle != nil {
+		caBundle = *googleworkspaceConfig.CABundle
+	}
+
+	if proxyURL == "" && caBundle == "" {
+		return nil, nil
+	}
+
+	// have we already created and cached the client?
+	cacheKey := clientCacheKey("googleworkspace.http_client", connectionName(d), "", nil)
+	client, err := clients.getOrCreate(cacheKey, func() (interface{}, error) {
+		return newBaseHTTPClient(proxyURL, caBundle)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client.(*http.Client), nil
+}
+
+// Returns a new HTTP client which sends requests through the given proxy, if any, and trusts
+// the certificates of the given CA bundle, if any, in addition to the system's ones
+func newBaseHTTPClient(proxyURL string, caBundle string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url: %v", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if caBundle != "" {
+		// Read the CA bundle from the given PEM string, or from the given path
+		bundle, err := pathOrContents(caBundle)
+		if err != nil {
+			return nil, err
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(bundle)) {
+			return nil, errors.New("ca_bundle contains no PEM encoded certificates")
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// Returns the context which token sources are created with, so that tokens are fetched using
+// the base HTTP client of the connection. Tokens are refreshed long after the query which created
+// the token source has completed, so the context must not be derived from the query's context.
+func tokenContext(d *plugin.QueryData) (context.Context, error) {
+	client, err := getBaseHTTPClient(d)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return context.Background(), nil
+	}
+	return context.WithValue(context.Background(), oauth2.HTTPClient, client), nil
+}
+
+// Returns the HTTP client carried by the given context, as set by tokenContext, or else the default client
+func contextHTTPClient(ctx context.Context) *http.Client {
+	if client, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && client != nil {
+		return client
+	}
+	return http.DefaultClient
+}
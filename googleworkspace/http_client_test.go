@@ -0,0 +1,77 @@
+package googleworkspace
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+func TestServiceEndpointOverrideAndCABundle(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/drive/v3/files" {
+			t.Errorf("request path = %q, want /drive/v3/files", r.URL.Path)
+		}
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"files": [{"id": "file1"}]}`))
+	}))
+	defer server.Close()
+
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	// A token which doesn't expire before the test completes is never refreshed
+	tokenPath := filepath.Join(t.TempDir(), "token.json")
+	err := writeTokenFile(tokenPath, &tokenFile{
+		Type:         "authorized_user",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RefreshToken: "refresh-token",
+		AccessToken:  "access-token",
+		TokenExpiry:  time.Now().Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := server.URL + "/drive/v3/"
+	d := &plugin.QueryData{
+		Connection: &plugin.Connection{
+			Name: t.Name(),
+			Config: googleworkspaceConfig{
+				TokenPath:     &tokenPath,
+				DriveEndpoint: &endpoint,
+				CABundle:      &caBundle,
+			},
+		},
+		Table: &plugin.Table{Name: "googleworkspace_drive"},
+	}
+
+	service, err := DriveService(context.Background(), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := service.Files.List().Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Files) != 1 || resp.Files[0].Id != "file1" {
+		t.Errorf("files = %+v, want file1", resp.Files)
+	}
+	if authorization != "Bearer access-token" {
+		t.Errorf("Authorization = %q, want Bearer access-token", authorization)
+	}
+}
+
+func TestNewBaseHTTPClientRejectsEmptyCABundle(t *testing.T) {
+	if _, err := newBaseHTTPClient("", "not a certificate"); err == nil {
+		t.Error("newBaseHTTPClient() accepted a CA bundle without certificates")
+	}
+}
@@ -0,0 +1,194 @@
+package googleworkspace
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// Login runs the OAuth 2.0 installed application flow, using a loopback redirect, and writes
+// the resulting token to the token file used by the token_path connection config argument. It
+// requests exactly the scopes used by the plugin's tables.
+// See https://developers.google.com/identity/protocols/oauth2/native-app
+func Login(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	clientSecretFile := flags.String("client-secret-file", "", "The path to the client secret JSON file of a Desktop app OAuth client.")
+	clientID := flags.String("client-id", "", "The client ID of a Desktop app OAuth client, if no client secret file is given.")
+	clientSecret := flags.String("client-secret", "", "The client secret of a Desktop app OAuth client, if no client secret file is given.")
+	tokenPath := flags.String("token-path", "", "The path of the token file to write, to set as token_path in the connection config.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *tokenPath == "" {
+		return errors.New("--token-path must be specified")
+	}
+	path, err := expandPath(*tokenPath)
+	if err != nil {
+		return err
+	}
+
+	config, err := loginConfig(*clientSecretFile, *clientID, *clientSecret)
+	if err != nil {
+		return err
+	}
+
+	// Listen on an ephemeral loopback port, which Desktop app clients accept as a redirect URI
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	config.RedirectURL = fmt.Sprintf("http://%s/", listener.Addr().String())
+
+	state, err := randomString()
+	if err != nil {
+		return err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL := config.AuthCodeURL(state,
+		oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("prompt", "consent"),
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+	fmt.Fprintf(os.Stderr, "Open the following URL in your browser, and sign in as the user to query Google Workspace as:\n\n%s\n\n", authURL)
+
+	code, err := waitForAuthCode(ctx, listener, state)
+	if err != nil {
+		return err
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken == "" {
+		return errors.New("no refresh token was returned; revoke the plugin's access to your account, and try again")
+	}
+
+	err = writeTokenFile(path, &tokenFile{
+		Type:         "authorized_user",
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		RefreshToken: token.RefreshToken,
+		AccessToken:  token.AccessToken,
+		TokenExpiry:  token.Expiry,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Token saved to %s. Set token_path = %q in the connection config.\n", path, *tokenPath)
+	return nil
+}
+
+func loginConfig(clientSecretFile string, clientID string, clientSecret string) (*oauth2.Config, error) {
+	if clientSecretFile != "" {
+		path, err := expandPath(clientSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return google.ConfigFromJSON(content, pluginScopes()...)
+	}
+
+	if clientID == "" {
+		return nil, errors.New("either --client-secret-file or --client-id must be specified")
+	}
+	return &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint:     google.Endpoint,
+		Scopes:       pluginScopes(),
+	}, nil
+}
+
+// How long the login subcommand waits for the user to complete the authorization in the browser
+const loginTimeout = 5 * time.Minute
+
+// waitForAuthCode serves the loopback redirect, and returns the authorization code it carries.
+// Requests which aren't an authorization response, e.g. a browser requesting the favicon, are
+// answered with a 404 and otherwise ignored.
+func waitForAuthCode(ctx context.Context, listener net.Listener, state string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") == "" && query.Get("code") == "" && query.Get("error") == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var res result
+		switch {
+		case query.Get("state") != state:
+			res.err = errors.New("the authorization response doesn't match the request")
+		case query.Get("error") != "":
+			res.err = fmt.Errorf("authorization failed: %s", query.Get("error"))
+		case query.Get("code") == "":
+			res.err = errors.New("the authorization response has no code")
+		default:
+			res.code = query.Get("code")
+		}
+
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Authorization complete. You can close this window.")
+		}
+
+		select {
+		case results <- res:
+		default:
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	select {
+	case res := <-results:
+		return res.code, res.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", errors.New("timed out waiting for the authorization to be completed in the browser")
+		}
+		return "", ctx.Err()
+	}
+}
+
+// Returns a random URL-safe string, used as the state and PKCE code verifier
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
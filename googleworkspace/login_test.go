@@ -0,0 +1,91 @@
+package googleworkspace
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// authCodeResult is the result of waitForAuthCode
+type authCodeResult struct {
+	code string
+	err  error
+}
+
+// Starts waitForAuthCode on a loopback listener, and returns its URL, and the channel receiving its result
+func startWaitForAuthCode(ctx context.Context, t *testing.T, state string) (string, chan authCodeResult) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	done := make(chan authCodeResult, 1)
+	go func() {
+		code, err := waitForAuthCode(ctx, listener, state)
+		done <- authCodeResult{code, err}
+	}()
+	return fmt.Sprintf("http://%s/", listener.Addr()), done
+}
+
+func TestWaitForAuthCodeIgnoresStrayRequests(t *testing.T) {
+	url, done := startWaitForAuthCode(context.Background(), t, "state-1")
+
+	// Browsers request the favicon of the redirect page
+	resp, err := http.Get(url + "favicon.ico")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("favicon status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	resp, err = http.Get(url + "?state=state-1&code=code-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	result := <-done
+	if result.err != nil {
+		t.Fatal(result.err)
+	}
+	if result.code != "code-1" {
+		t.Errorf("code = %q, want %q", result.code, "code-1")
+	}
+}
+
+func TestWaitForAuthCodeRejectsStateMismatch(t *testing.T) {
+	url, done := startWaitForAuthCode(context.Background(), t, "state-1")
+
+	resp, err := http.Get(url + "?state=state-2&code=code-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if result := <-done; result.err == nil {
+		t.Error("accepted an authorization response for another request")
+	}
+}
+
+func TestWaitForAuthCodeTimesOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, done := startWaitForAuthCode(ctx, t, "state-1")
+
+	select {
+	case result := <-done:
+		if result.err == nil {
+			t.Error("returned without an authorization response")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("still waiting after the context deadline")
+	}
+}
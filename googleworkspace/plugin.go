@@ -27,27 +27,67 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			NewInstance: ConfigInstance,
 			Schema:      ConfigSchema,
 		},
-		TableMap: map[string]*plugin.Table{
-			"googleworkspace_calendar":                     tableGoogleWorkspaceCalendar(ctx),
-			"googleworkspace_calendar_event":               tableGoogleWorkspaceCalendarEvent(ctx),
-			"googleworkspace_calendar_my_event":            tableGoogleWorkspaceCalendarMyEvent(ctx),
-			"googleworkspace_drive":                        tableGoogleWorkspaceDrive(ctx),
-			"googleworkspace_drive_my_file":                tableGoogleWorkspaceDriveMyFile(ctx),
-			"googleworkspace_gmail_draft":                  tableGoogleWorkspaceGmailDraft(ctx),
-			"googleworkspace_gmail_message":                tableGoogleWorkspaceGmailMessage(ctx),
-			"googleworkspace_gmail_my_draft":               tableGoogleWorkspaceGmailMyDraft(ctx),
-			"googleworkspace_gmail_my_message":             tableGoogleWorkspaceGmailMyMessage(ctx),
-			"googleworkspace_gmail_my_settings":            tableGoogleWorkspaceGmailMySettings(ctx),
-			"googleworkspace_gmail_settings":               tableGoogleWorkspaceGmailSettings(ctx),
-			"googleworkspace_people_contact":               tableGoogleWorkspacePeopleContact(ctx),
-			"googleworkspace_people_contact_group":         tableGoogleWorkspacePeopleContactGroup(ctx),
-			"googleworkspace_people_directory_people":      tableGoogleWorkspacePeopleDirectoryPeople(ctx),
-			"googleworkspace_admin_reports_activities":     tableGoogleWorkspaceAdminReportsActivities(ctx),
-			"googleworkspace_admin_reports_customer_usage": tableGoogleWorkspaceAdminReportsCustomerUsage(ctx),
-			"googleworkspace_admin_reports_user_usage":     tableGoogleWorkspaceAdminReportsUserUsage(ctx),
-			"googleworkspace_admin_reports_entity_usage":   tableGoogleWorkspaceAdminReportsEntityUsage(ctx),
-		},
+		TableMapFunc: pluginTableDefinitions,
 	}
 
 	return p
 }
+
+func pluginTableDefinitions(ctx context.Context, p *plugin.Plugin) (map[string]*plugin.Table, error) {
+	// Custom schema fields of users can be added as typed columns of the user table
+	userTable := tableGoogleWorkspaceUser(ctx)
+	customColumns, err := userCustomSchemaColumns(ctx, GetConfig(p.Connection))
+	if err != nil {
+		return nil, err
+	}
+	userTable.Columns = append(userTable.Columns, customColumns...)
+
+	tables := map[string]*plugin.Table{
+		"googleworkspace_api_usage":                              tableGoogleWorkspaceAPIUsage(ctx),
+		"googleworkspace_calendar":                               tableGoogleWorkspaceCalendar(ctx),
+		"googleworkspace_calendar_event":                         tableGoogleWorkspaceCalendarEvent(ctx),
+		"googleworkspace_calendar_my_event":                      tableGoogleWorkspaceCalendarMyEvent(ctx),
+		"googleworkspace_drive":                                  tableGoogleWorkspaceDrive(ctx),
+		"googleworkspace_drive_activity_audit":                   tableGoogleWorkspaceDriveActivityAudit(ctx),
+		"googleworkspace_drive_my_file":                          tableGoogleWorkspaceDriveMyFile(ctx),
+		"googleworkspace_gmail_draft":                            tableGoogleWorkspaceGmailDraft(ctx),
+		"googleworkspace_gmail_message":                          tableGoogleWorkspaceGmailMessage(ctx),
+		"googleworkspace_gmail_my_draft":                         tableGoogleWorkspaceGmailMyDraft(ctx),
+		"googleworkspace_gmail_my_message":                       tableGoogleWorkspaceGmailMyMessage(ctx),
+		"googleworkspace_gmail_my_settings":                      tableGoogleWorkspaceGmailMySettings(ctx),
+		"googleworkspace_gmail_settings":                         tableGoogleWorkspaceGmailSettings(ctx),
+		"googleworkspace_login_activity":                         tableGoogleWorkspaceLoginActivity(ctx),
+		"googleworkspace_people_contact":                         tableGoogleWorkspacePeopleContact(ctx),
+		"googleworkspace_people_contact_group":                   tableGoogleWorkspacePeopleContactGroup(ctx),
+		"googleworkspace_people_directory_people":                tableGoogleWorkspacePeopleDirectoryPeople(ctx),
+		"googleworkspace_admin_console_activity":                 tableGoogleWorkspaceAdminConsoleActivity(ctx),
+		"googleworkspace_admin_reports_activities":               tableGoogleWorkspaceAdminReportsActivities(ctx),
+		"googleworkspace_admin_reports_activity_event":           tableGoogleWorkspaceAdminReportsActivityEvent(ctx),
+		"googleworkspace_admin_reports_customer_usage":           tableGoogleWorkspaceAdminReportsCustomerUsage(ctx),
+		"googleworkspace_admin_reports_customer_usage_parameter": tableGoogleWorkspaceAdminReportsCustomerUsageParameter(ctx),
+		"googleworkspace_admin_reports_user_usage":               tableGoogleWorkspaceAdminReportsUserUsage(ctx),
+		"googleworkspace_admin_reports_user_usage_parameter":     tableGoogleWorkspaceAdminReportsUserUsageParameter(ctx),
+		"googleworkspace_admin_reports_entity_usage":             tableGoogleWorkspaceAdminReportsEntityUsage(ctx),
+		"googleworkspace_admin_reports_entity_usage_parameter":   tableGoogleWorkspaceAdminReportsEntityUsageParameter(ctx),
+		"googleworkspace_chromeos_device":                        tableGoogleWorkspaceChromeOSDevice(ctx),
+		"googleworkspace_customer":                               tableGoogleWorkspaceCustomer(ctx),
+		"googleworkspace_domain":                                 tableGoogleWorkspaceDomain(ctx),
+		"googleworkspace_domain_alias":                           tableGoogleWorkspaceDomainAlias(ctx),
+		"googleworkspace_group":                                  tableGoogleWorkspaceGroup(ctx),
+		"googleworkspace_group_member":                           tableGoogleWorkspaceGroupMember(ctx),
+		"googleworkspace_meet_activity":                          tableGoogleWorkspaceMeetActivity(ctx),
+		"googleworkspace_mobile_device":                          tableGoogleWorkspaceMobileDevice(ctx),
+		"googleworkspace_org_unit":                               tableGoogleWorkspaceOrgUnit(ctx),
+		"googleworkspace_privilege":                              tableGoogleWorkspacePrivilege(ctx),
+		"googleworkspace_resource_building":                      tableGoogleWorkspaceResourceBuilding(ctx),
+		"googleworkspace_resource_calendar":                      tableGoogleWorkspaceResourceCalendar(ctx),
+		"googleworkspace_resource_feature":                       tableGoogleWorkspaceResourceFeature(ctx),
+		"googleworkspace_role":                                   tableGoogleWorkspaceRole(ctx),
+		"googleworkspace_role_assignment":                        tableGoogleWorkspaceRoleAssignment(ctx),
+		"googleworkspace_user":                                   userTable,
+		"googleworkspace_user_schema":                            tableGoogleWorkspaceUserSchema(ctx),
+		"googleworkspace_user_token":                             tableGoogleWorkspaceUserToken(ctx),
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,273 @@
+package googleworkspace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+const (
+	// The number of times a rate limited or failed request is retried, unless configured otherwise
+	defaultMaxRetries = 5
+
+	// The bounds of the exponential backoff between retries, when the API doesn't say how long to wait
+	initialRetryDelay = 1 * time.Second
+	maxRetryDelay     = 32 * time.Second
+)
+
+// The reasons of the 403 errors returned when a rate limit or quota is exceeded, rather than
+// when access is denied. See https://developers.google.com/drive/api/guides/handle-errors
+var rateLimitReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"quotaExceeded":         true,
+}
+
+// retryTransport sends requests at the pace allowed by its rate limiters, and retries the ones
+// which were rate limited or failed on the server side. Unlike the vendored SendRequestWithRetry,
+// which only the generated Admin Reports client could use, it applies to the clients of every API.
+type retryTransport struct {
+	base       http.RoundTripper
+	limits     []rateLimit
+	maxRetries int
+
+	// sleep waits for the given duration, unless the context is done first
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int, limits ...rateLimit) *retryTransport {
+	return &retryTransport{
+		base:       base,
+		limits:     limits,
+		maxRetries: maxRetries,
+		sleep:      sleepContext,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		// The rate limiters are looked up for every request, so that the ones in use aren't evicted
+		for _, limit := range t.limits {
+			if err := limit.limiter().wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		// The body of the request has been consumed by the previous attempt. Retries are marked in
+		// their context, so that the usage of the APIs accounts them.
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(context.WithValue(ctx, retryAttemptKey{}, attempt))
+			if req.Body != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil || attempt >= t.maxRetries || !isRetryableResponse(resp) {
+			return resp, err
+		}
+
+		// A request whose body can't be re-created can't be retried
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		// Give up if the API asks to wait longer than the backoff ever would
+		delay := retryDelay(resp, attempt)
+		if delay > maxRetryDelay {
+			return resp, nil
+		}
+
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryableResponse returns true if the request was rate limited, or failed on the server side
+func isRetryableResponse(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return true
+	case resp.StatusCode == http.StatusForbidden:
+		return isRateLimitForbidden(resp)
+	}
+	return false
+}
+
+// isRateLimitForbidden returns true if the given 403 response reports an exceeded rate limit or
+// quota. The body is read, and replaced so that the error can still be decoded by the client.
+func isRateLimitForbidden(resp *http.Response) bool {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	var errResp struct {
+		Error struct {
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) != nil {
+		return false
+	}
+
+	for _, e := range errResp.Error.Errors {
+		if rateLimitReasons[e.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the given retry attempt: as long as the Retry-After
+// header of the response asks, if any, or else an exponential backoff with jitter
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			if delay := time.Until(date); delay > 0 {
+				return delay
+			}
+			return 0
+		}
+	}
+
+	ceiling := maxRetryDelay
+	if attempt < 6 {
+		if backoff := initialRetryDelay << uint(attempt); backoff < ceiling {
+			ceiling = backoff
+		}
+	}
+
+	// Wait between half and all of the backoff, so that concurrent requests don't retry in step
+	return ceiling/2 + time.Duration(rand.Int63n(int64(ceiling/2)+1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// tokenBucket allows up to rate requests per second on average, and bursts of up to rate requests
+type tokenBucket struct {
+	rate float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a request is allowed, or the context is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+
+	// Take the token now, even if it is only available later, so that waiting requests are queued
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		// Give the token back, since no request is sent
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// rateLimit is a rate limit applying to the requests of a service. The rate limiters of every
+// connection, API and user are kept in the client cache, so that the services of every table
+// querying an API share its limiters, and the limiters of users no longer queried are evicted.
+type rateLimit struct {
+	key  string
+	rate int
+}
+
+// Returns the rate limiter of the rate limit, creating it if it doesn't exist yet. Since the rate
+// is part of the cache key, changing the rate in the connection config creates a new limiter.
+func (l rateLimit) limiter() *tokenBucket {
+	limiter, _ := clients.getOrCreate(clientCacheKey("googleworkspace.rate_limiter", l.key, strconv.Itoa(l.rate), nil), func() (interface{}, error) {
+		return newTokenBucket(l.rate), nil
+	})
+	return limiter.(*tokenBucket)
+}
+
+// Returns the rate limits which apply to the requests of the given API, impersonating the given
+// user: one shared by every user of the API, and one for the user only, if configured
+func getRateLimits(d *plugin.QueryData, api string, subject string) []rateLimit {
+	googleworkspaceConfig := GetConfig(d.Connection)
+
+	var limits []rateLimit
+	if rate := googleworkspaceConfig.APIRequestsPerSecond; rate != nil && *rate > 0 {
+		limits = append(limits, rateLimit{clientCacheKey("googleworkspace.api_rate_limit", connectionName(d), "", []string{api}), *rate})
+	}
+	if rate := googleworkspaceConfig.UserRequestsPerSecond; rate != nil && *rate > 0 {
+		limits = append(limits, rateLimit{clientCacheKey("googleworkspace.user_rate_limit", connectionName(d), subject, []string{api}), *rate})
+	}
+
+	return limits
+}
+
+// Returns the number of times a rate limited or failed request is retried
+func maxRetries(d *plugin.QueryData) int {
+	googleworkspaceConfig := GetConfig(d.Connection)
+	if googleworkspaceConfig.MaxRetries != nil && *googleworkspaceConfig.MaxRetries >= 0 {
+		return *googleworkspaceConfig.MaxRetries
+	}
+	return defaultMaxRetries
+}
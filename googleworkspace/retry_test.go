@@ -0,0 +1,182 @@
+package googleworkspace
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns a client sending requests through a retry transport which records the delays it waits
+// instead of waiting, and a server answering with the given responses in turn
+func newRetryTestClient(t *testing.T, responses ...func(w http.ResponseWriter)) (*http.Client, *httptest.Server, *[]time.Duration) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, _ := ioutil.ReadAll(r.Body); r.Method == http.MethodPost && string(body) != "payload" {
+			t.Errorf("attempt %d sent body %q, want payload", requests+1, body)
+		}
+		if requests >= len(responses) {
+			t.Errorf("got %d requests, want %d", requests+1, len(responses))
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		responses[requests](w)
+		requests++
+	}))
+	t.Cleanup(server.Close)
+
+	var delays []time.Duration
+	transport := newRetryTransport(http.DefaultTransport, 3)
+	transport.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return &http.Client{Transport: transport}, server, &delays
+}
+
+func respond(status int, body string, headers ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func TestRetryTransportHonorsRetryAfter(t *testing.T) {
+	client, server, delays := newRetryTestClient(t,
+		respond(http.StatusTooManyRequests, "", "Retry-After", "7"),
+		respond(http.StatusOK, "ok"),
+	)
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+		t.Errorf("waited %v, want 7s", *delays)
+	}
+}
+
+func TestRetryTransportGivesUpOnLongRetryAfter(t *testing.T) {
+	client, server, delays := newRetryTestClient(t,
+		respond(http.StatusTooManyRequests, "", "Retry-After", "3600"),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", resp.StatusCode)
+	}
+	if len(*delays) != 0 {
+		t.Errorf("waited %v, want no retry", *delays)
+	}
+}
+
+func TestRetryTransportBacksOffOnServerErrorsAndRateLimitForbidden(t *testing.T) {
+	client, server, delays := newRetryTestClient(t,
+		respond(http.StatusServiceUnavailable, ""),
+		respond(http.StatusForbidden, `{"error": {"code": 403, "errors": [{"reason": "userRateLimitExceeded"}]}}`),
+		respond(http.StatusOK, "ok"),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+
+	// The backoff doubles at every attempt, and is jittered down to half of it at most
+	if len(*delays) != 2 {
+		t.Fatalf("waited %d times, want 2", len(*delays))
+	}
+	for i, delay := range *delays {
+		backoff := initialRetryDelay << uint(i)
+		if delay < backoff/2 || delay > backoff {
+			t.Errorf("retry %d waited %v, want between %v and %v", i+1, delay, backoff/2, backoff)
+		}
+	}
+}
+
+func TestRetryTransportDoesNotRetryAccessDenied(t *testing.T) {
+	body := `{"error": {"code": 403, "errors": [{"reason": "forbidden"}]}}`
+	client, server, delays := newRetryTestClient(t, respond(http.StatusForbidden, body))
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*delays) != 0 {
+		t.Errorf("retried %d times, want none", len(*delays))
+	}
+
+	// The body was read to check the reason, but must still be readable by the client
+	if got, _ := ioutil.ReadAll(resp.Body); string(got) != body {
+		t.Errorf("body = %s, want %s", got, body)
+	}
+}
+
+func TestRetryTransportGivesUpAfterMaxRetries(t *testing.T) {
+	client, server, delays := newRetryTestClient(t,
+		respond(http.StatusTooManyRequests, ""),
+		respond(http.StatusTooManyRequests, ""),
+		respond(http.StatusTooManyRequests, ""),
+		respond(http.StatusTooManyRequests, ""),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", resp.StatusCode)
+	}
+	if len(*delays) != 3 {
+		t.Errorf("retried %d times, want 3", len(*delays))
+	}
+}
+
+func TestTokenBucketLimitsRate(t *testing.T) {
+	bucket := newTokenBucket(10)
+
+	// The burst is allowed right away, and the next requests at the rate
+	start := time.Now()
+	for i := 0; i < 13; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("13 requests at 10 per second took %v, want about 300ms", elapsed)
+	}
+
+	// A request which gives up waiting doesn't use up a token
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := bucket.wait(ctx); err == nil {
+		t.Error("wait() returned no error once the context was cancelled")
+	}
+}
+
+func TestRateLimitSharesLimiter(t *testing.T) {
+	limit := rateLimit{key: t.Name(), rate: 10}
+	if limit.limiter() != (rateLimit{key: t.Name(), rate: 10}).limiter() {
+		t.Error("the same rate limit has several limiters")
+	}
+
+	// Changing the rate in the connection config replaces the limiter
+	if limit.limiter() == (rateLimit{key: t.Name(), rate: 20}).limiter() {
+		t.Error("the limiter of a rate limit is reused for another rate")
+	}
+}
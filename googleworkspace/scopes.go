@@ -0,0 +1,190 @@
+package googleworkspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/people/v1"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+// The scopes each API can be accessed with
+var apiScopes = map[string][]string{
+	"admin_reports": {
+		AdminReportsAuditReadonlyScope,
+		AdminReportsUsageReadonlyScope,
+	},
+	"calendar": {
+		calendar.CalendarReadonlyScope,
+	},
+	"directory": {
+		admin.AdminDirectoryCustomerReadonlyScope,
+		admin.AdminDirectoryDeviceChromeosReadonlyScope,
+		admin.AdminDirectoryDeviceMobileReadonlyScope,
+		admin.AdminDirectoryDomainReadonlyScope,
+		admin.AdminDirectoryGroupReadonlyScope,
+		admin.AdminDirectoryOrgunitReadonlyScope,
+		admin.AdminDirectoryResourceCalendarReadonlyScope,
+		admin.AdminDirectoryRolemanagementReadonlyScope,
+		admin.AdminDirectoryUserReadonlyScope,
+		admin.AdminDirectoryUserschemaReadonlyScope,
+	},
+	"drive": {
+		drive.DriveReadonlyScope,
+	},
+	"gmail": {
+		gmail.GmailReadonlyScope,
+	},
+	"people": {
+		people.ContactsOtherReadonlyScope,
+		people.ContactsReadonlyScope,
+		people.DirectoryReadonlyScope,
+	},
+}
+
+// The scopes of each API which grant more than read access. They are only requested for the
+// tables which declare them, never when falling back to all the scopes of the API.
+var optInScopes = map[string][]string{
+	"directory": {
+		admin.AdminDirectoryUserSecurityScope,
+	},
+}
+
+// The scopes each table needs. Only the scopes of the table being queried are requested
+// when impersonating a user, so the domain-wide delegation grant of the service account
+// only needs the scopes of the tables it is used for.
+var tableScopes = map[string][]string{
+	"googleworkspace_admin_console_activity":                 {AdminReportsAuditReadonlyScope},
+	"googleworkspace_admin_reports_activities":               {AdminReportsAuditReadonlyScope},
+	"googleworkspace_admin_reports_activity_event":           {AdminReportsAuditReadonlyScope},
+	"googleworkspace_admin_reports_customer_usage":           {AdminReportsUsageReadonlyScope},
+	"googleworkspace_admin_reports_customer_usage_parameter": {AdminReportsUsageReadonlyScope},
+	"googleworkspace_admin_reports_entity_usage":             {AdminReportsUsageReadonlyScope},
+	"googleworkspace_admin_reports_entity_usage_parameter":   {AdminReportsUsageReadonlyScope},
+	"googleworkspace_admin_reports_user_usage":               {AdminReportsUsageReadonlyScope},
+	"googleworkspace_admin_reports_user_usage_parameter":     {AdminReportsUsageReadonlyScope},
+	"googleworkspace_calendar":                               {calendar.CalendarReadonlyScope},
+	"googleworkspace_calendar_event":                         {calendar.CalendarReadonlyScope},
+	"googleworkspace_calendar_my_event":                      {calendar.CalendarReadonlyScope},
+	"googleworkspace_chromeos_device":                        {admin.AdminDirectoryDeviceChromeosReadonlyScope},
+	"googleworkspace_customer":                               {admin.AdminDirectoryCustomerReadonlyScope},
+	"googleworkspace_domain":                                 {admin.AdminDirectoryDomainReadonlyScope},
+	"googleworkspace_domain_alias":                           {admin.AdminDirectoryDomainReadonlyScope},
+	"googleworkspace_drive":                                  {drive.DriveReadonlyScope},
+	"googleworkspace_drive_activity_audit":                   {AdminReportsAuditReadonlyScope},
+	"googleworkspace_drive_my_file":                          {drive.DriveReadonlyScope},
+	"googleworkspace_gmail_draft":                            {gmail.GmailReadonlyScope, admin.AdminDirectoryUserReadonlyScope},
+	"googleworkspace_gmail_message":                          {gmail.GmailReadonlyScope, admin.AdminDirectoryUserReadonlyScope},
+	"googleworkspace_gmail_my_draft":                         {gmail.GmailReadonlyScope},
+	"googleworkspace_gmail_my_message":                       {gmail.GmailReadonlyScope},
+	"googleworkspace_gmail_my_settings":                      {gmail.GmailReadonlyScope},
+	"googleworkspace_gmail_settings":                         {gmail.GmailReadonlyScope, admin.AdminDirectoryUserReadonlyScope},
+	"googleworkspace_group":                                  {admin.AdminDirectoryGroupReadonlyScope},
+	"googleworkspace_group_member":                           {admin.AdminDirectoryGroupReadonlyScope},
+	"googleworkspace_login_activity":                         {AdminReportsAuditReadonlyScope},
+	"googleworkspace_meet_activity":                          {AdminReportsAuditReadonlyScope},
+	"googleworkspace_mobile_device":                          {admin.AdminDirectoryDeviceMobileReadonlyScope},
+	"googleworkspace_org_unit":                               {admin.AdminDirectoryOrgunitReadonlyScope},
+	"googleworkspace_people_contact":                         {people.ContactsReadonlyScope},
+	"googleworkspace_people_contact_group":                   {people.ContactsReadonlyScope},
+	"googleworkspace_people_directory_people":                {people.DirectoryReadonlyScope},
+	"googleworkspace_privilege":                              {admin.AdminDirectoryRolemanagementReadonlyScope},
+	"googleworkspace_resource_building":                      {admin.AdminDirectoryResourceCalendarReadonlyScope},
+	"googleworkspace_resource_calendar":                      {admin.AdminDirectoryResourceCalendarReadonlyScope},
+	"googleworkspace_resource_feature":                       {admin.AdminDirectoryResourceCalendarReadonlyScope},
+	"googleworkspace_role":                                   {admin.AdminDirectoryRolemanagementReadonlyScope},
+	"googleworkspace_role_assignment":                        {admin.AdminDirectoryRolemanagementReadonlyScope, admin.AdminDirectoryUserReadonlyScope, admin.AdminDirectoryGroupReadonlyScope},
+	"googleworkspace_user":                                   {admin.AdminDirectoryUserReadonlyScope},
+	"googleworkspace_user_schema":                            {admin.AdminDirectoryUserschemaReadonlyScope},
+	"googleworkspace_user_token":                             {admin.AdminDirectoryUserSecurityScope, admin.AdminDirectoryUserReadonlyScope},
+}
+
+// scopesForQuery returns the scopes to request to access the given API, for the table being queried.
+// If the table doesn't declare any scope of the API, all the scopes of the API are requested.
+func scopesForQuery(d *plugin.QueryData, api string) []string {
+	allScopes := append(append([]string{}, apiScopes[api]...), optInScopes[api]...)
+
+	var scopes []string
+	for _, scope := range tableScopes[tableName(d)] {
+		for _, apiScope := range allScopes {
+			if scope == apiScope {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	if len(scopes) == 0 {
+		return apiScopes[api]
+	}
+	return scopes
+}
+
+// Returns the name of the table being queried
+func tableName(d *plugin.QueryData) string {
+	if d.Table == nil {
+		return ""
+	}
+	return d.Table.Name
+}
+
+// missingScopeTokenSource wraps the token source of a table, and turns the error returned when
+// the service account isn't authorized for the requested scopes into one naming the missing scopes.
+type missingScopeTokenSource struct {
+	base   oauth2.TokenSource
+	table  string
+	scopes []string
+
+	// probe requests a token with the given scope only
+	probe func(scope string) error
+
+	once    sync.Once
+	missing []string
+}
+
+func (ts *missingScopeTokenSource) Token() (*oauth2.Token, error) {
+	token, err := ts.base.Token()
+	if err == nil || !isUnauthorizedClientError(err) {
+		return token, err
+	}
+
+	// The token endpoint doesn't say which scope is missing, so find out by requesting each scope on its own
+	ts.once.Do(func() {
+		for _, scope := range ts.scopes {
+			if probeErr := ts.probe(scope); probeErr != nil && isUnauthorizedClientError(probeErr) {
+				ts.missing = append(ts.missing, scope)
+			}
+		}
+	})
+
+	if len(ts.missing) == 0 {
+		return nil, fmt.Errorf("table %s requires the scopes %s, but the service account isn't authorized for all of them: %v", ts.table, strings.Join(ts.scopes, ", "), err)
+	}
+	return nil, fmt.Errorf("table %s requires the %s scope, which isn't granted to the service account's domain-wide delegation", ts.table, strings.Join(ts.missing, ", "))
+}
+
+// Returns true if the token endpoint rejected the request, because the service account
+// isn't authorized for the requested scopes
+func isUnauthorizedClientError(err error) bool {
+	var rerr *oauth2.RetrieveError
+	if !errors.As(err, &rerr) {
+		return false
+	}
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(rerr.Body, &body) != nil {
+		return false
+	}
+
+	return body.Error == "unauthorized_client"
+}
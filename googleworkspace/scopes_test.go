@@ -0,0 +1,115 @@
+package googleworkspace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+func TestScopesForQuery(t *testing.T) {
+	tests := []struct {
+		table *plugin.Table
+		api   string
+		want  []string
+	}{
+		{tableGoogleWorkspaceGroup(context.Background()), "directory", []string{admin.AdminDirectoryGroupReadonlyScope}},
+		{tableGoogleWorkspaceAdminReportsActivities(context.Background()), "admin_reports", []string{AdminReportsAuditReadonlyScope}},
+		// The Gmail tables which fan out across users only request the Gmail scope from the Gmail API
+		{tableGoogleWorkspaceGmailMessage(context.Background()), "gmail", []string{gmail.GmailReadonlyScope}},
+		{tableGoogleWorkspaceGmailMessage(context.Background()), "directory", []string{admin.AdminDirectoryUserReadonlyScope}},
+		// A table which doesn't declare any scope of the API falls back to all of them
+		{tableGoogleWorkspaceGroup(context.Background()), "admin_reports", apiScopes["admin_reports"]},
+	}
+
+	for _, test := range tests {
+		d := &plugin.QueryData{Table: test.table}
+		if got := scopesForQuery(d, test.api); strings.Join(got, " ") != strings.Join(test.want, " ") {
+			t.Errorf("%s %s scopes = %v, want %v", test.table.Name, test.api, got, test.want)
+		}
+	}
+}
+
+// Returns the error returned by the token endpoint when the service account isn't authorized for the requested scopes
+func unauthorizedClientError() error {
+	return &oauth2.RetrieveError{Body: []byte(`{"error": "unauthorized_client", "error_description": "Client is unauthorized to retrieve access tokens using this method"}`)}
+}
+
+type failingTokenSource struct {
+	err error
+}
+
+func (ts failingTokenSource) Token() (*oauth2.Token, error) {
+	return nil, ts.err
+}
+
+func TestMissingScopeTokenSourceNamesMissingScope(t *testing.T) {
+	granted := admin.AdminDirectoryUserReadonlyScope
+	missing := admin.AdminDirectoryUserSecurityScope
+	ts := &missingScopeTokenSource{
+		base:   failingTokenSource{unauthorizedClientError()},
+		table:  "googleworkspace_user_token",
+		scopes: []string{missing, granted},
+		probe: func(scope string) error {
+			if scope == missing {
+				return unauthorizedClientError()
+			}
+			return nil
+		},
+	}
+
+	_, err := ts.Token()
+	if err == nil {
+		t.Fatal("got a token without the missing scope")
+	}
+	if !strings.Contains(err.Error(), "googleworkspace_user_token") || !strings.Contains(err.Error(), missing) || strings.Contains(err.Error(), granted) {
+		t.Errorf("error %q doesn't name the table and only the missing scope %s", err, missing)
+	}
+}
+
+func TestMissingScopeTokenSourcePassesOtherErrors(t *testing.T) {
+	baseErr := errors.New("connection refused")
+	ts := &missingScopeTokenSource{
+		base:   failingTokenSource{baseErr},
+		table:  "googleworkspace_user",
+		scopes: []string{admin.AdminDirectoryUserReadonlyScope},
+		probe: func(scope string) error {
+			t.Errorf("probed the %s scope for an error which isn't unauthorized_client", scope)
+			return nil
+		},
+	}
+
+	if _, err := ts.Token(); err != baseErr {
+		t.Errorf("error = %v, want %v", err, baseErr)
+	}
+}
+
+func TestScopesForQueryOptInScopes(t *testing.T) {
+	// The user token table declares the user security scope, so it is requested for it
+	d := &plugin.QueryData{Table: tableGoogleWorkspaceUserToken(context.Background())}
+	if !containsScope(scopesForQuery(d, "directory"), admin.AdminDirectoryUserSecurityScope) {
+		t.Errorf("%s doesn't request the %s scope", tableName(d), admin.AdminDirectoryUserSecurityScope)
+	}
+
+	// A table which doesn't declare scopes falls back to the read-only scopes of the API
+	d = &plugin.QueryData{Table: tableGoogleWorkspaceUser(context.Background())}
+	d.Table.Name = "googleworkspace_undeclared"
+	if containsScope(scopesForQuery(d, "directory"), admin.AdminDirectoryUserSecurityScope) {
+		t.Errorf("%s requests the %s scope", tableName(d), admin.AdminDirectoryUserSecurityScope)
+	}
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
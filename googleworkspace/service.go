@@ -2,10 +2,16 @@ package googleworkspace
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/jwt"
+	admin "google.golang.org/api/admin/directory/v1"
 	"google.golang.org/api/calendar/v3"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/gmail/v1"
@@ -16,135 +22,151 @@ import (
 )
 
 func CalendarService(ctx context.Context, d *plugin.QueryData) (*calendar.Service, error) {
-	// have we already created and cached the service?
-	serviceCacheKey := "googleworkspace.calendar"
-	if cachedData, ok := d.ConnectionManager.Cache.Get(serviceCacheKey); ok {
-		return cachedData.(*calendar.Service), nil
-	}
-
-	// so it was not in cache - create service
-	opts, err := getSessionConfig(ctx, d)
+	svc, err := getService(ctx, d, "calendar", "", nil, func(ctx context.Context, opts ...option.ClientOption) (interface{}, error) {
+		return calendar.NewService(ctx, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	// Create service
-	svc, err := calendar.NewService(ctx, opts...)
-	if err != nil {
-		return nil, err
-	}
-
-	// cache the service
-	d.ConnectionManager.Cache.Set(serviceCacheKey, svc)
-
-	return svc, nil
+	return svc.(*calendar.Service), nil
 }
 
 func PeopleService(ctx context.Context, d *plugin.QueryData) (*people.Service, error) {
-	// have we already created and cached the service?
-	serviceCacheKey := "googleworkspace.people"
-	if cachedData, ok := d.ConnectionManager.Cache.Get(serviceCacheKey); ok {
-		return cachedData.(*people.Service), nil
-	}
-
-	// so it was not in cache - create service
-	opts, err := getSessionConfig(ctx, d)
+	svc, err := getService(ctx, d, "people", "", nil, func(ctx context.Context, opts ...option.ClientOption) (interface{}, error) {
+		return people.NewService(ctx, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return svc.(*people.Service), nil
+}
 
-	// Create service
-	svc, err := people.NewService(ctx, opts...)
+func DriveService(ctx context.Context, d *plugin.QueryData) (*drive.Service, error) {
+	svc, err := getService(ctx, d, "drive", "", nil, func(ctx context.Context, opts ...option.ClientOption) (interface{}, error) {
+		return drive.NewService(ctx, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	// cache the service
-	d.ConnectionManager.Cache.Set(serviceCacheKey, svc)
-
-	return svc, nil
+	return svc.(*drive.Service), nil
 }
 
-func DriveService(ctx context.Context, d *plugin.QueryData) (*drive.Service, error) {
-	// have we already created and cached the service?
-	serviceCacheKey := "googleworkspace.drive"
-	if cachedData, ok := d.ConnectionManager.Cache.Get(serviceCacheKey); ok {
-		return cachedData.(*drive.Service), nil
-	}
+func GmailService(ctx context.Context, d *plugin.QueryData) (*gmail.Service, error) {
+	return GmailServiceForUser(ctx, d, "")
+}
 
-	// so it was not in cache - create service
-	opts, err := getSessionConfig(ctx, d)
+// GmailServiceForUser returns a Gmail service which impersonates the given user, using
+// domain-wide delegation. The Gmail API only allows a user to access their own mailbox.
+// If the user is empty, the connection's configured credentials are used.
+func GmailServiceForUser(ctx context.Context, d *plugin.QueryData, userEmail string) (*gmail.Service, error) {
+	svc, err := getService(ctx, d, "gmail", userEmail, nil, func(ctx context.Context, opts ...option.ClientOption) (interface{}, error) {
+		return gmail.NewService(ctx, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return svc.(*gmail.Service), nil
+}
 
-	// Create service
-	svc, err := drive.NewService(ctx, opts...)
+func AdminReportsService(ctx context.Context, d *plugin.QueryData) (*Service, error) {
+	svc, err := getService(ctx, d, "admin_reports", "", nil, func(ctx context.Context, opts ...option.ClientOption) (interface{}, error) {
+		return NewService(ctx, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	// cache the service
-	d.ConnectionManager.Cache.Set(serviceCacheKey, svc)
-
-	return svc, nil
+	return svc.(*Service), nil
 }
 
-func GmailService(ctx context.Context, d *plugin.QueryData) (*gmail.Service, error) {
-	// have we already created and cached the service?
-	serviceCacheKey := "googleworkspace.gmail"
-	if cachedData, ok := d.ConnectionManager.Cache.Get(serviceCacheKey); ok {
-		return cachedData.(*gmail.Service), nil
-	}
-
-	// so it was not in cache - create service
-	opts, err := getSessionConfig(ctx, d)
+// DirectoryService returns a Directory service with the scopes of the table being queried. Tables
+// which need several scopes can request a subset for each call, so that the scopes only needed
+// by some columns or quals don't have to be granted to use the rest of the table.
+func DirectoryService(ctx context.Context, d *plugin.QueryData, scopes ...string) (*admin.Service, error) {
+	svc, err := getService(ctx, d, "directory", "", scopes, func(ctx context.Context, opts ...option.ClientOption) (interface{}, error) {
+		return admin.NewService(ctx, opts...)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return svc.(*admin.Service), nil
+}
 
-	// Create service
-	svc, err := gmail.NewService(ctx, opts...)
-	if err != nil {
-		return nil, err
+// getService returns the service of the given API which authenticates as the given subject with
+// the scopes of the table being queried, or the requested scopes if any, creating it with newService
+// if it isn't cached yet. An empty subject uses the connection's configured credentials as they are.
+func getService(ctx context.Context, d *plugin.QueryData, api string, subject string, requestedScopes []string, newService func(context.Context, ...option.ClientOption) (interface{}, error)) (interface{}, error) {
+	scopes := requestedScopes
+	if len(scopes) == 0 {
+		scopes = scopesForQuery(d, api)
 	}
 
-	// cache the service
-	d.ConnectionManager.Cache.Set(serviceCacheKey, svc)
+	// have we already created and cached the service?
+	// Services are cached per table, since their token source names the table in errors
+	serviceCacheKey := clientCacheKey("googleworkspace."+api+"."+tableName(d), connectionName(d), subject, scopes)
+	return clients.getOrCreate(serviceCacheKey, func() (interface{}, error) {
+		// so it was not in cache - create service
+		opts, err := getSessionConfig(ctx, d, api, subject, scopes)
+		if err != nil {
+			return nil, err
+		}
 
-	return svc, nil
+		// Services outlive the query which created them, so they must not be bound to its context
+		return newService(context.Background(), opts...)
+	})
 }
 
-func AdminReportsService(ctx context.Context, d *plugin.QueryData) (*Service, error) {
-	// have we already created and cached the service?
-	serviceCacheKey := "googleworkspace.AdminReports"
-	if cachedData, ok := d.ConnectionManager.Cache.Get(serviceCacheKey); ok {
-		return cachedData.(*Service), nil
-	}
+// getSessionConfig returns the client options of a service of the given API, which authenticates
+// as the given subject with the given scopes, and sends its requests to the configured endpoint,
+// through the configured proxy, at the configured rate, retrying the rate limited ones.
+func getSessionConfig(ctx context.Context, d *plugin.QueryData, api string, subject string, scopes []string) ([]option.ClientOption, error) {
+	opts := []option.ClientOption{}
 
-	// so it was not in cache - create service
-	opts, err := getSessionConfig(ctx, d)
+	ts, err := getSessionTokenSource(ctx, d, subject, scopes)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create service
-	svc, err := NewService(ctx, opts...)
+	googleworkspaceConfig := GetConfig(d.Connection)
+	if endpoint := apiEndpoint(googleworkspaceConfig, api); endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	base := http.DefaultTransport
+	baseClient, err := getBaseHTTPClient(d)
 	if err != nil {
 		return nil, err
 	}
+	if baseClient != nil {
+		base = baseClient.Transport
+	}
 
-	// cache the service
-	d.ConnectionManager.Cache.Set(serviceCacheKey, svc)
+	// Requests are rate limited per impersonated user, which is the configured one unless given
+	limitedSubject := subject
+	if limitedSubject == "" && googleworkspaceConfig.ImpersonatedUserEmail != nil {
+		limitedSubject = *googleworkspaceConfig.ImpersonatedUserEmail
+	}
 
-	return svc, nil
-}
+	// A custom HTTP client replaces the one the client library would authenticate, so it must
+	// authenticate the requests itself. Every retry is authenticated with the current token, and
+	// every attempt is accounted in the usage of the APIs of the table.
+	usage := &usageTransport{
+		base:       &oauth2.Transport{Source: ts, Base: base},
+		connection: connectionName(d),
+		api:        api,
+		table:      tableName(d),
+		basePath:   apiBasePath(googleworkspaceConfig, api),
+	}
+	transport := newRetryTransport(usage, maxRetries(d), getRateLimits(d, api, limitedSubject)...)
+	opts = append(opts, option.WithHTTPClient(&http.Client{Transport: transport}))
 
-func getSessionConfig(ctx context.Context, d *plugin.QueryData) ([]option.ClientOption, error) {
-	opts := []option.ClientOption{}
+	return opts, nil
+}
 
+// getSessionTokenSource returns the TokenSource which authenticates as the given subject with the
+// given scopes, using the credentials of the connection.
+func getSessionTokenSource(ctx context.Context, d *plugin.QueryData, subject string, scopes []string) (oauth2.TokenSource, error) {
 	// Get credential file path, and user to impersonate from config (if mentioned)
-	var credentialContent, tokenPath string
+	var credentialContent, tokenPath, impersonateServiceAccount string
 	googleworkspaceConfig := GetConfig(d.Connection)
 
 	// 'credential_file' in connection config is DEPRECATED, and will be removed in future release
@@ -159,14 +181,68 @@ func getSessionConfig(ctx context.Context, d *plugin.QueryData) ([]option.Client
 		tokenPath = *googleworkspaceConfig.TokenPath
 	}
 
-	// If credential path provided, use domain-wide delegation
-	if credentialContent != "" {
-		ts, err := getTokenSource(ctx, d)
+	if googleworkspaceConfig.ImpersonateServiceAccount != nil {
+		impersonateServiceAccount = *googleworkspaceConfig.ImpersonateServiceAccount
+	}
+
+	// Token sources are created with a context which carries the base HTTP client of the connection, if any
+	tsCtx, err := tokenContext(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Workload identity federation credentials can't impersonate users on their own, since only a
+	// service account can be delegated domain-wide authority. Unless a service account is impersonated,
+	// authenticate as the federated identity.
+	if credentialContent != "" && impersonateServiceAccount == "" {
+		content, err := pathOrContents(credentialContent)
+		if err != nil {
+			return nil, err
+		}
+		if credentialsType(content) == "external_account" {
+			if subject != "" {
+				return nil, fmt.Errorf("impersonate_service_account must be configured to impersonate %s using external_account credentials", subject)
+			}
+			// The federated identity can't act as the configured user, so don't silently ignore it
+			if googleworkspaceConfig.ImpersonatedUserEmail != nil && *googleworkspaceConfig.ImpersonatedUserEmail != "" {
+				return nil, errors.New("impersonate_service_account must be configured to use impersonated_user_email with external_account credentials")
+			}
+			credentials, err := google.CredentialsFromJSON(tsCtx, []byte(content), scopes...)
+			if err != nil {
+				return nil, err
+			}
+			return credentials.TokenSource, nil
+		}
+	}
+
+	// If credential path provided, a service account to sign delegation JWTs as is provided, or a
+	// specific user must be impersonated, use domain-wide delegation
+	if credentialContent != "" || impersonateServiceAccount != "" || subject != "" {
+		if subject == "" {
+			// Return error, since impersonation required to authenticate using domain-wide delegation
+			if googleworkspaceConfig.ImpersonatedUserEmail == nil || *googleworkspaceConfig.ImpersonatedUserEmail == "" {
+				return nil, errors.New("impersonated_user_email must be configured")
+			}
+			subject = *googleworkspaceConfig.ImpersonatedUserEmail
+		}
+
+		ts, err := getTokenSource(ctx, d, subject, scopes)
 		if err != nil {
 			return nil, err
 		}
-		opts = append(opts, option.WithTokenSource(ts))
-		return opts, nil
+		return &missingScopeTokenSource{
+			base:   ts,
+			table:  tableName(d),
+			scopes: scopes,
+			probe: func(scope string) error {
+				ts, err := newDelegatedTokenSource(ctx, d, subject, []string{scope})
+				if err != nil {
+					return err
+				}
+				_, err = ts.Token()
+				return err
+			},
+		}, nil
 	}
 
 	// If token path provided, authenticate using OAuth 2.0
@@ -175,27 +251,135 @@ func getSessionConfig(ctx context.Context, d *plugin.QueryData) ([]option.Client
 		if err != nil {
 			return nil, err
 		}
-		opts = append(opts, option.WithCredentialsFile(path))
-		return opts, nil
+
+		// Token files written by the login subcommand are refreshed by the plugin, which writes
+		// refreshed tokens back to the file
+		ts, err := clients.getOrCreate(clientCacheKey("googleworkspace.token_file", connectionName(d), path, nil), func() (interface{}, error) {
+			if ts, ok := tokenFileTokenSource(tsCtx, path); ok {
+				return ts, nil
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ts != nil {
+			return ts.(oauth2.TokenSource), nil
+		}
+
+		// Other credential files, such as the ones written by gcloud, are used as they are
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		credentials, err := google.CredentialsFromJSON(tsCtx, content, scopes...)
+		if err != nil {
+			return nil, err
+		}
+		return credentials.TokenSource, nil
+	}
+
+	// Otherwise, use the Application Default Credentials
+	credentials, err := google.FindDefaultCredentials(tsCtx, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.TokenSource, nil
+}
+
+// Returns a TokenSource which impersonates the given user with the given scopes, using domain-wide delegation.
+func getTokenSource(ctx context.Context, d *plugin.QueryData, subject string, scopes []string) (oauth2.TokenSource, error) {
+	// have we already created and cached the token?
+	cacheKey := clientCacheKey("googleworkspace.token_source", connectionName(d), subject, scopes)
+	ts, err := clients.getOrCreate(cacheKey, func() (interface{}, error) {
+		return newDelegatedTokenSource(ctx, d, subject, scopes)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	return ts.(oauth2.TokenSource), nil
 }
 
-// Returns a JWT TokenSource using the configuration and the HTTP client from the provided context.
-func getTokenSource(ctx context.Context, d *plugin.QueryData) (oauth2.TokenSource, error) {
+// Returns a new TokenSource which impersonates the given user with the given scopes. The delegation
+// JWT is signed with the key of the service account in the credentials, or, if a service account to
+// impersonate is configured, by the IAM Credentials API as that service account (keyless delegation).
+func newDelegatedTokenSource(ctx context.Context, d *plugin.QueryData, subject string, scopes []string) (oauth2.TokenSource, error) {
+	googleworkspaceConfig := GetConfig(d.Connection)
+
+	if googleworkspaceConfig.ImpersonateServiceAccount != nil && *googleworkspaceConfig.ImpersonateServiceAccount != "" {
+		source, err := getSourceTokenSource(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		tsCtx, err := tokenContext(d)
+		if err != nil {
+			return nil, err
+		}
+		return newSignJWTTokenSource(tsCtx, source, *googleworkspaceConfig.ImpersonateServiceAccount, subject, scopes), nil
+	}
+
 	// Note: based on https://developers.google.com/admin-sdk/directory/v1/guides/delegation#go
+	config, err := getJWTConfig(googleworkspaceConfig, subject, scopes)
+	if err != nil {
+		return nil, err
+	}
 
+	tsCtx, err := tokenContext(d)
+	if err != nil {
+		return nil, err
+	}
+	return config.TokenSource(tsCtx), nil
+}
+
+// Returns the TokenSource of the credentials used to call the IAM Credentials API for keyless
+// delegation: the configured credentials, which may be a workload identity federation config,
+// or else the Application Default Credentials.
+func getSourceTokenSource(ctx context.Context, d *plugin.QueryData) (oauth2.TokenSource, error) {
 	// have we already created and cached the token?
-	cacheKey := "googleworkspace.token_source"
-	if ts, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
-		return ts.(oauth2.TokenSource), nil
+	cacheKey := clientCacheKey("googleworkspace.source_token_source", connectionName(d), "", []string{cloudPlatformScope})
+	ts, err := clients.getOrCreate(cacheKey, func() (interface{}, error) {
+		googleworkspaceConfig := GetConfig(d.Connection)
+		var creds string
+		if googleworkspaceConfig.Credentials != nil {
+			creds = *googleworkspaceConfig.Credentials
+		} else if googleworkspaceConfig.CredentialFile != nil {
+			creds = *googleworkspaceConfig.CredentialFile
+		}
+
+		tsCtx, err := tokenContext(d)
+		if err != nil {
+			return nil, err
+		}
+
+		if creds == "" {
+			credentials, err := google.FindDefaultCredentials(tsCtx, cloudPlatformScope)
+			if err != nil {
+				return nil, err
+			}
+			return credentials.TokenSource, nil
+		}
+
+		credentialContent, err := pathOrContents(creds)
+		if err != nil {
+			return nil, err
+		}
+		credentials, err := google.CredentialsFromJSON(tsCtx, []byte(credentialContent), cloudPlatformScope)
+		if err != nil {
+			return nil, err
+		}
+		return credentials.TokenSource, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Get credential file path, and user to impersonate from config (if mentioned)
-	var impersonateUser string
-	googleworkspaceConfig := GetConfig(d.Connection)
+	return ts.(oauth2.TokenSource), nil
+}
 
+// Returns the JWT configuration which impersonates the given user with the given scopes,
+// read from the service account credentials of the connection.
+func getJWTConfig(googleworkspaceConfig googleworkspaceConfig, subject string, scopes []string) (*jwt.Config, error) {
 	// Read credential from JSON string, or from the given path
 	// NOTE: 'credential_file' in connection config is DEPRECATED, and will be removed in future release
 	// use `credentials` instead
@@ -206,42 +390,41 @@ func getTokenSource(ctx context.Context, d *plugin.QueryData) (oauth2.TokenSourc
 		creds = *googleworkspaceConfig.CredentialFile
 	}
 
-	// Read credential from JSON string, or from the given path
+	// Return error, since a service account is required to impersonate users
+	if creds == "" {
+		return nil, errors.New("credentials must be configured to impersonate users using domain-wide delegation")
+	}
+
 	credentialContent, err := pathOrContents(creds)
 	if err != nil {
 		return nil, err
 	}
 
-	if googleworkspaceConfig.ImpersonatedUserEmail != nil {
-		impersonateUser = *googleworkspaceConfig.ImpersonatedUserEmail
-	}
-
-	// Return error, since impersonation required to authenticate using domain-wide delegation
-	if impersonateUser == "" {
-		return nil, errors.New("impersonated_user_email must be configured")
-	}
-
 	// Authorize the request
-	config, err := google.JWTConfigFromJSON(
-		[]byte(credentialContent),
-		calendar.CalendarReadonlyScope,
-		drive.DriveReadonlyScope,
-		gmail.GmailReadonlyScope,
-		people.ContactsOtherReadonlyScope,
-		people.ContactsReadonlyScope,
-		people.DirectoryReadonlyScope,
-		AdminReportsAuditReadonlyScope,
-		AdminReportsUsageReadonlyScope,
-	)
+	config, err := google.JWTConfigFromJSON([]byte(credentialContent), scopes...)
 	if err != nil {
 		return nil, err
 	}
-	config.Subject = impersonateUser
+	config.Subject = subject
 
-	ts := config.TokenSource(ctx)
+	return config, nil
+}
 
-	// cache the token source
-	d.ConnectionManager.Cache.Set(cacheKey, ts)
+// Returns the type of the given JSON credentials, e.g. "service_account" or "external_account"
+func credentialsType(content string) string {
+	var credentials struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal([]byte(content), &credentials) != nil {
+		return ""
+	}
+	return credentials.Type
+}
 
-	return ts, nil
+// Returns the name of the connection being queried, which is part of the cache keys of token sources and services
+func connectionName(d *plugin.QueryData) string {
+	if d.Connection == nil {
+		return ""
+	}
+	return d.Connection.Name
 }
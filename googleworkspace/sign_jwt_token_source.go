@@ -0,0 +1,151 @@
+package googleworkspace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// The IAM Credentials API signs the delegation JWT on behalf of the target service account
+	defaultIAMCredentialsEndpoint = "https://iamcredentials.googleapis.com"
+
+	// The scope the source credentials need to call the IAM Credentials API
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	// The lifetime of the delegation JWT, and of the access token exchanged for it
+	signedJWTLifetime = time.Hour
+)
+
+// signJWTTokenSource implements keyless domain-wide delegation. Rather than signing the
+// delegation JWT with a service account key, it asks the IAM Credentials API to sign it as
+// the target service account, using the source credentials, and then exchanges the signed
+// JWT for an access token of the impersonated user.
+// See https://cloud.google.com/iam/docs/reference/credentials/rest/v1/projects.serviceAccounts/signJwt
+type signJWTTokenSource struct {
+	// client is authenticated with the source credentials, which must be granted
+	// roles/iam.serviceAccountTokenCreator on the target service account
+	client *http.Client
+
+	// tokenClient exchanges the signed JWT, which is its own credential
+	tokenClient *http.Client
+
+	iamCredentialsEndpoint string
+	tokenURL               string
+
+	serviceAccount string
+	subject        string
+	scopes         []string
+}
+
+// newSignJWTTokenSource returns a token source which impersonates the subject through the
+// given service account, using keyless domain-wide delegation. Requests are sent using the
+// HTTP client of the given context, if any.
+func newSignJWTTokenSource(ctx context.Context, source oauth2.TokenSource, serviceAccount string, subject string, scopes []string) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &signJWTTokenSource{
+		client:                 oauth2.NewClient(ctx, source),
+		tokenClient:            contextHTTPClient(ctx),
+		iamCredentialsEndpoint: defaultIAMCredentialsEndpoint,
+		tokenURL:               google.JWTTokenURL,
+		serviceAccount:         serviceAccount,
+		subject:                subject,
+		scopes:                 scopes,
+	})
+}
+
+func (ts *signJWTTokenSource) Token() (*oauth2.Token, error) {
+	signedJWT, err := ts.signJWT()
+	if err != nil {
+		return nil, err
+	}
+	return ts.exchangeJWT(signedJWT)
+}
+
+func (ts *signJWTTokenSource) signJWT() (string, error) {
+	now := time.Now()
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   ts.serviceAccount,
+		"sub":   ts.subject,
+		"scope": strings.Join(ts.scopes, " "),
+		"aud":   ts.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(signedJWTLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(map[string]string{"payload": string(claims)})
+	if err != nil {
+		return "", err
+	}
+
+	signURL := fmt.Sprintf("%s/v1/projects/-/serviceAccounts/%s:signJwt", strings.TrimSuffix(ts.iamCredentialsEndpoint, "/"), url.PathEscape(ts.serviceAccount))
+	resp, err := ts.client.Post(signURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("unable to sign the delegation JWT as %s: %v", ts.serviceAccount, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unable to sign the delegation JWT as %s: %s: %s", ts.serviceAccount, resp.Status, respBody)
+	}
+
+	var signResp struct {
+		SignedJWT string `json:"signedJwt"`
+	}
+	if err := json.Unmarshal(respBody, &signResp); err != nil {
+		return "", err
+	}
+
+	return signResp.SignedJWT, nil
+}
+
+func (ts *signJWTTokenSource) exchangeJWT(signedJWT string) (*oauth2.Token, error) {
+	resp, err := ts.tokenClient.PostForm(ts.tokenURL, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signedJWT},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the same error as the JWT flow, so that missing scopes are detected the same way
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &oauth2.RetrieveError{Response: resp, Body: body}
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: tokenResp.AccessToken,
+		TokenType:   tokenResp.TokenType,
+		Expiry:      time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,220 @@
+package googleworkspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+const testServiceAccount = "steampipe@project.iam.gserviceaccount.com"
+
+// newTestSignJWTTokenSource returns a token source which calls the given stand-ins for the IAM
+// Credentials API and the OAuth 2.0 token endpoint
+func newTestSignJWTTokenSource(source oauth2.TokenSource, iam *httptest.Server, token *httptest.Server, subject string, scopes []string) *signJWTTokenSource {
+	return &signJWTTokenSource{
+		client:                 oauth2.NewClient(context.Background(), source),
+		tokenClient:            token.Client(),
+		iamCredentialsEndpoint: iam.URL,
+		tokenURL:               token.URL + "/token",
+		serviceAccount:         testServiceAccount,
+		subject:                subject,
+		scopes:                 scopes,
+	}
+}
+
+// newTestIAMServer returns a stand-in for the IAM Credentials API, which "signs" the claims by echoing them
+func newTestIAMServer(t *testing.T, wantAuthorization string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if want := "/v1/projects/-/serviceAccounts/" + testServiceAccount + ":signJwt"; r.URL.Path != want {
+			t.Errorf("signJwt path = %q, want %q", r.URL.Path, want)
+		}
+		if got := r.Header.Get("Authorization"); got != wantAuthorization {
+			t.Errorf("signJwt Authorization = %q, want %q", got, wantAuthorization)
+		}
+
+		var req struct {
+			Payload string `json:"payload"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"keyId": "1", "signedJwt": "signed." + req.Payload})
+	}))
+}
+
+// newTestTokenServer returns a stand-in for the OAuth 2.0 token endpoint, which records the claims
+// of the assertion, and rejects requests for the given scope as the token endpoint does when the
+// scope isn't granted to the service account
+func newTestTokenServer(t *testing.T, claims map[string]interface{}, deniedScope string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.FormValue("grant_type"); got != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("grant_type = %q", got)
+		}
+
+		assertion := r.FormValue("assertion")
+		if err := json.Unmarshal([]byte(assertion[len("signed."):]), &claims); err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if claims["scope"] == deniedScope {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"unauthorized_client","error_description":"Client is unauthorized to retrieve access tokens using this method, or client not authorized for any of the scopes requested."}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"user-token","token_type":"Bearer","expires_in":3600}`)
+	}))
+}
+
+func TestSignJWTTokenSource(t *testing.T) {
+	claims := map[string]interface{}{}
+	iam := newTestIAMServer(t, "Bearer source-token")
+	defer iam.Close()
+	token := newTestTokenServer(t, claims, "")
+	defer token.Close()
+
+	source := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "source-token"})
+	ts := newTestSignJWTTokenSource(source, iam, token, "admin@example.com", []string{"scope.a", "scope.b"})
+
+	got, err := ts.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != "user-token" {
+		t.Errorf("AccessToken = %q, want %q", got.AccessToken, "user-token")
+	}
+
+	for claim, want := range map[string]interface{}{
+		"iss":   testServiceAccount,
+		"sub":   "admin@example.com",
+		"scope": "scope.a scope.b",
+		"aud":   token.URL + "/token",
+	} {
+		if claims[claim] != want {
+			t.Errorf("claim %s = %v, want %v", claim, claims[claim], want)
+		}
+	}
+}
+
+func TestSignJWTTokenSourceMissingScope(t *testing.T) {
+	iam := newTestIAMServer(t, "Bearer source-token")
+	defer iam.Close()
+	token := newTestTokenServer(t, map[string]interface{}{}, "scope.denied")
+	defer token.Close()
+
+	source := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "source-token"})
+	ts := &missingScopeTokenSource{
+		base:   newTestSignJWTTokenSource(source, iam, token, "admin@example.com", []string{"scope.denied"}),
+		table:  "googleworkspace_test",
+		scopes: []string{"scope.denied"},
+		probe: func(scope string) error {
+			_, err := newTestSignJWTTokenSource(source, iam, token, "admin@example.com", []string{scope}).Token()
+			return err
+		},
+	}
+
+	_, err := ts.Token()
+	want := "table googleworkspace_test requires the scope.denied scope, which isn't granted to the service account's domain-wide delegation"
+	if err == nil || err.Error() != want {
+		t.Errorf("Token() error = %v, want %q", err, want)
+	}
+}
+
+// Workload identity federation: a subject token read from a file is exchanged by the STS for a
+// federated token, which is then used to sign the delegation JWT as the target service account
+func TestSignJWTTokenSourceExternalAccount(t *testing.T) {
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.FormValue("subject_token"); got != "oidc-token" {
+			t.Errorf("subject_token = %q, want %q", got, "oidc-token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"federated-token","issued_token_type":"urn:ietf:params:oauth:token-type:access_token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer sts.Close()
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("oidc-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	credentials := fmt.Sprintf(`{
+		"type": "external_account",
+		"audience": "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url": %q,
+		"credential_source": {"file": %q}
+	}`, "https://sts.googleapis.com/v1/token", tokenFile)
+	if got := credentialsType(credentials); got != "external_account" {
+		t.Errorf("credentialsType() = %q, want %q", got, "external_account")
+	}
+
+	// The token URL must be a Google STS endpoint, so route its requests to the stand-in instead
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: redirectTransport{sts}})
+	source, err := google.CredentialsFromJSON(ctx, []byte(credentials), cloudPlatformScope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iam := newTestIAMServer(t, "Bearer federated-token")
+	defer iam.Close()
+	token := newTestTokenServer(t, map[string]interface{}{}, "")
+	defer token.Close()
+
+	got, err := newTestSignJWTTokenSource(source.TokenSource, iam, token, "admin@example.com", []string{"scope.a"}).Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != "user-token" {
+		t.Errorf("AccessToken = %q, want %q", got.AccessToken, "user-token")
+	}
+}
+
+// redirectTransport sends every request to the given test server, whatever its original host
+type redirectTransport struct {
+	server *httptest.Server
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	target, err := url.Parse(t.server.URL)
+	if err != nil {
+		return nil, err
+	}
+	r = r.Clone(r.Context())
+	r.URL.Scheme = target.Scheme
+	r.URL.Host = target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// Without a service account to impersonate, external_account credentials authenticate as the
+// federated identity, so a configured user to impersonate is a config error
+func TestExternalAccountRequiresServiceAccountToImpersonateUser(t *testing.T) {
+	credentials := `{
+		"type": "external_account",
+		"audience": "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url": "https://sts.googleapis.com/v1/token",
+		"credential_source": {"file": "/var/run/token"}
+	}`
+	impersonatedUserEmail := "admin@example.com"
+	d := &plugin.QueryData{Connection: &plugin.Connection{
+		Name:   t.Name(),
+		Config: googleworkspaceConfig{Credentials: &credentials, ImpersonatedUserEmail: &impersonatedUserEmail},
+	}}
+
+	_, err := getSessionTokenSource(context.Background(), d, "", []string{cloudPlatformScope})
+	if err == nil || !strings.Contains(err.Error(), "impersonate_service_account") {
+		t.Errorf("error = %v, want one requiring impersonate_service_account", err)
+	}
+}
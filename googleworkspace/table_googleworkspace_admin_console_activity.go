@@ -0,0 +1,153 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceAdminConsoleActivity(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_admin_console_activity",
+		Description: "Changes made by the administrators of the Google Workspace account in the Admin console, from the admin activity report.",
+		List: &plugin.ListConfig{
+			Hydrate: listAdminConsoleActivities,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "user_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "actor_ip_address",
+					Require: plugin.Optional,
+				},
+				{
+					Name:      "time",
+					Require:   plugin.Optional,
+					Operators: []string{">", ">=", "=", "<", "<="},
+				},
+				{
+					Name:    "event_name",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "time",
+				Description: "The time of the change.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Id.Time"),
+			},
+			{
+				Name:        "event_name",
+				Description: "The name of the change, e.g. CHANGE_APPLICATION_SETTING, CREATE_USER or ADD_GROUP_MEMBER.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Name"),
+			},
+			{
+				Name:        "event_type",
+				Description: "The kind of settings changed, e.g. APPLICATION_SETTINGS, USER_SETTINGS or GROUP_SETTINGS.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Type"),
+			},
+			{
+				Name:        "email",
+				Description: "The primary email address of the administrator who made the change.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.Email"),
+			},
+			{
+				Name:        "application_name",
+				Description: "The name of the application whose setting was changed, e.g. Drive and Docs or Gmail.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.APPLICATION_NAME"),
+			},
+			{
+				Name:        "setting_name",
+				Description: "The name of the setting which was changed.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.SETTING_NAME"),
+			},
+			{
+				Name:        "old_value",
+				Description: "The value of the setting before the change.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.OLD_VALUE"),
+			},
+			{
+				Name:        "new_value",
+				Description: "The value of the setting after the change.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.NEW_VALUE"),
+			},
+			{
+				Name:        "org_unit_name",
+				Description: "The path of the organizational unit the change applies to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.ORG_UNIT_NAME"),
+			},
+			{
+				Name:        "user_email",
+				Description: "The primary email address of the user the change applies to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.USER_EMAIL"),
+			},
+			{
+				Name:        "group_email",
+				Description: "The primary email address of the group the change applies to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.GROUP_EMAIL"),
+			},
+			{
+				Name:        "parameters",
+				Description: "All the parameters of the change by name, with plain JSON values.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters"),
+			},
+			{
+				Name:        "user_key",
+				Description: "The email address or profile ID of the administrator to list the changes of, as given in the where clause. Otherwise, the email address of the administrator who made the change, or the unique identifier of the actor if it isn't a user.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor").Transform(activityUserKey),
+			},
+			{
+				Name:        "actor_ip_address",
+				Description: "The IP address of the administrator who made the change.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("IpAddress"),
+			},
+			{
+				Name:        "unique_qualifier",
+				Description: "Unique qualifier of the activity the change belongs to, if multiple activities have the same time.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.UniqueQualifier"),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listAdminConsoleActivities(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	call, err := newActivitiesListCall(ctx, d, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	// The quals on the time column can't match any activity
+	if call == nil {
+		return nil, nil
+	}
+
+	// Every event of the admin report is a single change, e.g. of a setting
+	if err := listActivityEvents(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
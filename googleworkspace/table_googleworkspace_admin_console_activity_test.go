@@ -0,0 +1,64 @@
+package googleworkspace
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestListAdminConsoleActivitiesDecodesSettingChanges(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminConsoleActivity(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{})
+	if len(items) != 3 {
+		t.Fatalf("listed %d changes, want 3", len(items))
+	}
+
+	columns := []string{"event_name", "email", "application_name", "setting_name", "old_value", "new_value", "org_unit_name", "user_email", "group_email"}
+	want := []map[string]interface{}{
+		{
+			"event_name":       "CHANGE_APPLICATION_SETTING",
+			"email":            "admin@example.com",
+			"application_name": "Drive and Docs",
+			"setting_name":     "Sharing settings - External sharing",
+			"old_value":        "ALLOWLISTED_DOMAINS",
+			"new_value":        "ALLOWED",
+			"org_unit_name":    "/Engineering",
+			"user_email":       nil,
+			"group_email":      nil,
+		},
+		{
+			"event_name":       "CHANGE_PASSWORD",
+			"email":            "admin@example.com",
+			"application_name": nil,
+			"setting_name":     nil,
+			"old_value":        nil,
+			"new_value":        nil,
+			"org_unit_name":    nil,
+			"user_email":       "john@example.com",
+			"group_email":      nil,
+		},
+		{
+			"event_name":       "ADD_GROUP_MEMBER",
+			"email":            "admin@example.com",
+			"application_name": nil,
+			"setting_name":     nil,
+			"old_value":        nil,
+			"new_value":        nil,
+			"org_unit_name":    nil,
+			"user_email":       "jane@example.com",
+			"group_email":      "admins@example.com",
+		},
+	}
+	for i, item := range items {
+		if got := columnValues(t, table, item, columns...); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("change %d = %v, want %v", i, got, want[i])
+		}
+	}
+
+	// Without a user key in the where clause, the user key is the administrator's email address
+	if userKey := columnValues(t, table, items[0], "user_key")["user_key"]; userKey != "admin@example.com" {
+		t.Errorf("user_key = %v, want admin@example.com", userKey)
+	}
+}
@@ -0,0 +1,188 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListAdminReportsActivitiesPushesDownQuals(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsActivities(context.Background())
+
+	listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("application_name", stringValue("login")),
+			equalsQual("event_name", stringValue("login_failure")),
+			equalsQual("actor_ip_address", stringValue("203.0.113.11")),
+			{Column: "time", Operator: ">=", Value: timestampValue("2022-02-01T00:00:00Z")},
+			{Column: "time", Operator: "<", Value: timestampValue("2022-02-02T00:00:00Z")},
+		},
+	})
+
+	request := server.requests("/admin/reports/v1/activity/users/all/applications/login")[0]
+	want := map[string]string{
+		"eventName":      "login_failure",
+		"actorIpAddress": "203.0.113.11",
+		"startTime":      "2022-02-01T00:00:00.000Z",
+		"endTime":        "2022-02-01T23:59:59.999Z",
+		"maxResults":     "1000",
+	}
+	for name, value := range want {
+		if got := request.Query.Get(name); got != value {
+			t.Errorf("%s = %s, want %s", name, got, value)
+		}
+	}
+}
+
+func TestListAdminReportsActivitiesTimeRange(t *testing.T) {
+	table := tableGoogleWorkspaceAdminReportsActivities(context.Background())
+
+	cases := []struct {
+		name      string
+		quals     []*quals.Qual
+		startTime string
+		endTime   string
+	}{
+		{
+			name:      "after a time in another timezone",
+			quals:     []*quals.Qual{{Column: "time", Operator: ">", Value: timestampValue("2022-02-01T09:00:00+09:00")}},
+			startTime: "2022-02-01T00:00:00.001Z",
+		},
+		{
+			name:    "until a time",
+			quals:   []*quals.Qual{{Column: "time", Operator: "<=", Value: timestampValue("2022-02-01T12:30:00Z")}},
+			endTime: "2022-02-01T12:30:00.000Z",
+		},
+		{
+			name:      "at a time",
+			quals:     []*quals.Qual{equalsQual("time", timestampValue("2022-02-01T09:00:00Z"))},
+			startTime: "2022-02-01T09:00:00.000Z",
+			endTime:   "2022-02-01T09:00:00.000Z",
+		},
+		{
+			name:    "before a time with microseconds",
+			quals:   []*quals.Qual{{Column: "time", Operator: "<", Value: timestampValue("2022-02-01T09:00:00.000500Z")}},
+			endTime: "2022-02-01T09:00:00.000Z",
+		},
+		{
+			name: "within overlapping ranges",
+			quals: []*quals.Qual{
+				{Column: "time", Operator: ">=", Value: timestampValue("2022-01-01T00:00:00Z")},
+				{Column: "time", Operator: ">=", Value: timestampValue("2022-02-01T00:00:00Z")},
+				{Column: "time", Operator: "<", Value: timestampValue("2022-03-01T00:00:00Z")},
+				{Column: "time", Operator: "<", Value: timestampValue("2022-04-01T00:00:00Z")},
+			},
+			startTime: "2022-02-01T00:00:00.000Z",
+			endTime:   "2022-02-28T23:59:59.999Z",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newReplayServer(t, "admin_reports")
+			listRows(t, table, server.connection(), testQuery{
+				quals: append([]*quals.Qual{equalsQual("application_name", stringValue("login"))}, c.quals...),
+			})
+
+			request := server.requests("/admin/reports/v1/activity/users/all/applications/login")[0]
+			if startTime := request.Query.Get("startTime"); startTime != c.startTime {
+				t.Errorf("startTime = %s, want %s", startTime, c.startTime)
+			}
+			if endTime := request.Query.Get("endTime"); endTime != c.endTime {
+				t.Errorf("endTime = %s, want %s", endTime, c.endTime)
+			}
+		})
+	}
+}
+
+func TestListAdminReportsActivitiesSkipsEmptyTimeRange(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsActivities(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("application_name", stringValue("login")),
+			{Column: "time", Operator: ">", Value: timestampValue("2022-02-01T00:00:00Z")},
+			{Column: "time", Operator: "<", Value: timestampValue("2022-02-01T00:00:00Z")},
+		},
+	})
+	requests := server.requests("/admin/reports/v1/activity/users/all/applications/login")
+	if len(items) != 0 || len(requests) != 0 {
+		t.Errorf("listed %d activities with %d requests, want none", len(items), len(requests))
+	}
+}
+
+func TestListAdminReportsActivitiesDefaultWindow(t *testing.T) {
+	table := tableGoogleWorkspaceAdminReportsActivities(context.Background())
+	q := testQuery{quals: []*quals.Qual{equalsQual("application_name", stringValue("login"))}}
+
+	cases := []struct {
+		window string
+		want   time.Duration
+	}{
+		{"", defaultActivityWindow},
+		{"7d", 7 * 24 * time.Hour},
+		{"36h", 36 * time.Hour},
+		{"0", 0},
+	}
+	for _, c := range cases {
+		t.Run(c.window, func(t *testing.T) {
+			server := newReplayServer(t, "admin_reports")
+			connection := server.connection()
+			if c.window != "" {
+				config := connection.Config.(googleworkspaceConfig)
+				config.ActivityDefaultWindow = &c.window
+				connection.Config = config
+			}
+			listRows(t, table, connection, q)
+
+			request := server.requests("/admin/reports/v1/activity/users/all/applications/login")[0]
+			if request.Query.Get("endTime") != "" {
+				t.Errorf("endTime = %s, want none", request.Query.Get("endTime"))
+			}
+			if c.want == 0 {
+				if startTime := request.Query.Get("startTime"); startTime != "" {
+					t.Errorf("startTime = %s, want none", startTime)
+				}
+				return
+			}
+			startTime, err := time.Parse(time.RFC3339, request.Query.Get("startTime"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if window := time.Since(startTime); window < c.want || window > c.want+time.Minute {
+				t.Errorf("startTime = %s, want %v ago", startTime, c.want)
+			}
+		})
+	}
+}
+
+func TestListAdminReportsActivitiesPaginatesUntilLimit(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsActivities(context.Background())
+	q := testQuery{quals: []*quals.Qual{equalsQual("application_name", stringValue("login"))}}
+
+	items := listRows(t, table, server.connection(), q)
+	if len(items) != 3 {
+		t.Errorf("listed %d activities, want 3", len(items))
+	}
+	if pages := len(server.requests("/admin/reports/v1/activity/users/all/applications/login")); pages != 2 {
+		t.Errorf("listed %d pages, want 2", pages)
+	}
+
+	server = newReplayServer(t, "admin_reports")
+	q.limit = limit(2)
+	items = listRows(t, table, server.connection(), q)
+	if len(items) != 2 {
+		t.Errorf("listed %d activities, want 2", len(items))
+	}
+	requests := server.requests("/admin/reports/v1/activity/users/all/applications/login")
+	if len(requests) != 1 {
+		t.Fatalf("listed %d pages, want 1", len(requests))
+	}
+	if maxResults := requests[0].Query.Get("maxResults"); maxResults != "2" {
+		t.Errorf("maxResults = %s, want the limit", maxResults)
+	}
+}
@@ -0,0 +1,185 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceAdminReportsActivityEvent(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_admin_reports_activity_event",
+		Description: "Events of the activity reports of one application, one row per event.",
+		List: &plugin.ListConfig{
+			Hydrate: listAdminReportsActivityEvents,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "application_name",
+					Require: plugin.Required,
+				},
+				{
+					Name:    "user_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "actor_ip_address",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "customer_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:      "time",
+					Require:   plugin.Optional,
+					Operators: []string{">", ">=", "=", "<", "<="},
+				},
+				{
+					Name:    "event_name",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "filters",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "org_unit_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "group_id_filter",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "application_name",
+				Description: "The name of the application the event belongs to, e.g. login, drive or admin.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.ApplicationName"),
+			},
+			{
+				Name:        "time",
+				Description: "The time of the activity the event belongs to.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Id.Time"),
+			},
+			{
+				Name:        "unique_qualifier",
+				Description: "Unique qualifier of the activity the event belongs to, if multiple activities have the same time.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.UniqueQualifier"),
+			},
+			{
+				Name:        "event_index",
+				Description: "The position of the event in the events of its activity, starting at 0.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("EventIndex"),
+			},
+			{
+				Name:        "event_type",
+				Description: "The type of the event, which groups related events, e.g. login or access.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Type"),
+			},
+			{
+				Name:        "event_name",
+				Description: "The name of the event, e.g. login_success or edit.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Name"),
+			},
+			{
+				Name:        "parameters",
+				Description: "The parameters of the event by name, with plain JSON values.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters"),
+			},
+			{
+				Name:        "user_key",
+				Description: "The email address or profile ID of the user to list the events of, as given in the where clause. Otherwise, the email address of the actor, or its unique identifier if the actor isn't a user.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor").Transform(activityUserKey),
+			},
+			{
+				Name:        "email",
+				Description: "The primary email address of the actor.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.Email"),
+			},
+			{
+				Name:        "profile_id",
+				Description: "The unique Google Workspace profile ID of the actor.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.ProfileId"),
+			},
+			{
+				Name:        "caller_type",
+				Description: "The type of actor, e.g. USER or KEY.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.CallerType"),
+			},
+			{
+				Name:        "actor_ip_address",
+				Description: "The IP address of the actor.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("IpAddress"),
+			},
+			{
+				Name:        "customer_id",
+				Description: "The unique identifier of the Google Workspace account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.CustomerId"),
+			},
+			{
+				Name:        "owner_domain",
+				Description: "The domain which is affected by the event.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "filters",
+				Description: "A query string to filter the events on their parameters, e.g. doc_id==12345.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("filters"),
+			},
+			{
+				Name:        "org_unit_id",
+				Description: "ID of the organizational unit to report on.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("org_unit_id"),
+			},
+			{
+				Name:        "group_id_filter",
+				Description: "Group ids on which user activities are filtered.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("group_id_filter"),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listAdminReportsActivityEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	applicationName := d.KeyColumnQuals["application_name"].GetStringValue()
+
+	call, err := newActivitiesListCall(ctx, d, applicationName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The quals on the time column can't match any activity
+	if call == nil {
+		return nil, nil
+	}
+
+	if err := listActivityEvents(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,95 @@
+package googleworkspace
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListAdminReportsActivityEventsNormalizesParameters(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsActivityEvent(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{equalsQual("application_name", stringValue("drive"))},
+	})
+	if len(items) != 2 {
+		t.Fatalf("listed %d events, want 2", len(items))
+	}
+
+	event := items[1].(activityEvent)
+	if event.Event.Type != "acl_change" || event.Event.Name != "change_user_access" || event.EventIndex != 1 {
+		t.Errorf("event %d is %s %s, want 1 acl_change change_user_access", event.EventIndex, event.Event.Type, event.Event.Name)
+	}
+
+	columns := columnValues(t, table, event, "email", "time", "event_type")
+	if columns["email"] != "jane@example.com" || columns["time"] != "2022-02-01T12:00:00.000Z" || columns["event_type"] != "acl_change" {
+		t.Errorf("email, time and event_type = %v, want jane@example.com, 2022-02-01T12:00:00.000Z and acl_change", columns)
+	}
+
+	got, err := json.Marshal(map[string]interface{}{
+		"doc_id":             event.Parameters["doc_id"],
+		"new_value":          event.Parameters["new_value"],
+		"primary_event":      event.Parameters["primary_event"],
+		"revision":           event.Parameters["revision"],
+		"label_field_change": event.Parameters["label_field_change"],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"doc_id":"doc-1","label_field_change":{"count":2,"scope":"external"},"new_value":["can_view"],"primary_event":false,"revision":42}`
+	if string(got) != want {
+		t.Errorf("parameters = %s, want %s", got, want)
+	}
+}
+
+func TestListAdminReportsActivityEventsPushesDownEventName(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsActivityEvent(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("application_name", stringValue("drive")),
+			equalsQual("event_name", stringValue("edit")),
+			{Column: "time", Operator: ">", Value: timestampValue("2022-02-01T00:00:00Z")},
+		},
+	})
+
+	// The other events of the activities with a matching event are left out
+	if len(items) != 1 || items[0].(activityEvent).Event.Name != "edit" {
+		t.Errorf("listed %v, want the edit event only", items)
+	}
+
+	request := server.requests("/admin/reports/v1/activity/users/all/applications/drive")[0]
+	if eventName := request.Query.Get("eventName"); eventName != "edit" {
+		t.Errorf("eventName = %s, want edit", eventName)
+	}
+	if startTime := request.Query.Get("startTime"); startTime != "2022-02-01T00:00:00.001Z" {
+		t.Errorf("startTime = %s, want 2022-02-01T00:00:00.001Z", startTime)
+	}
+}
+
+func TestAdminReportsActivityEventUserKey(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsActivityEvent(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{equalsQual("application_name", stringValue("drive"))},
+	})
+	if len(items) == 0 {
+		t.Fatal("listed no events")
+	}
+
+	// Without a user key in the where clause, the user key is the actor's email address
+	if userKey := columnValues(t, table, items[0], "user_key")["user_key"]; userKey != "jane@example.com" {
+		t.Errorf("user_key = %v, want jane@example.com", userKey)
+	}
+
+	// The user key given in the where clause, e.g. a profile ID, is returned as is, for the rows to match it
+	qual := equalsQual("user_key", stringValue("110000000000000000001"))
+	if userKey := qualColumnValue(t, table, items[0], "user_key", qual); userKey != "110000000000000000001" {
+		t.Errorf("user_key = %v, want 110000000000000000001", userKey)
+	}
+}
@@ -79,6 +79,34 @@ func tableGoogleWorkspaceAdminReportsCustomerUsage(_ context.Context) *plugin.Ta
 //// LIST FUNCTION
 
 func listAdminReportsCustomerUsage(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	resp, err := newCustomerUsageReportCall(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+
+	if err := resp.Pages(ctx, func(page *UsageReports) error {
+		for _, item := range page.UsageReports {
+			d.StreamListItem(ctx, item)
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// newCustomerUsageReportCall returns the call getting the customer usage report matching the quals of the query,
+// or nil if the query has no date
+func newCustomerUsageReportCall(ctx context.Context, d *plugin.QueryData) (*CustomerUsageReportsGetCall, error) {
 	// Create service
 	service, err := AdminReportsService(ctx, d)
 	if err != nil {
@@ -100,25 +128,9 @@ func listAdminReportsCustomerUsage(ctx context.Context, d *plugin.QueryData, _ *
 		resp = resp.CustomerId(customer_id)
 	}
 
-	var parameters string
-	if d.KeyColumnQuals["parameters"] != nil {
-		parameters = d.KeyColumnQuals["parameters"].GetStringValue()
+	if parameters := usageReportParametersQual(d); parameters != "" {
 		resp = resp.Parameters(parameters)
 	}
 
-	if err := resp.Pages(ctx, func(page *UsageReports) error {
-		for _, item := range page.UsageReports {
-			d.StreamListItem(ctx, item)
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if plugin.IsCancelled(ctx) {
-				page.NextPageToken = ""
-				break
-			}
-		}
-		return nil
-	}); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
+	return resp, nil
 }
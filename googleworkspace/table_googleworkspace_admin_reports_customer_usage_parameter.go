@@ -0,0 +1,35 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceAdminReportsCustomerUsageParameter(ctx context.Context) *plugin.Table {
+	return usageReportParameterTable(
+		tableGoogleWorkspaceAdminReportsCustomerUsage(ctx),
+		"Retrieves the parameters of the usage reports of the customer, one row per date and parameter",
+		listAdminReportsCustomerUsageParameters,
+	)
+}
+
+//// LIST FUNCTION
+
+func listAdminReportsCustomerUsageParameters(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	call, err := newCustomerUsageReportCall(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		return nil, nil
+	}
+
+	if err := listUsageReportParameters(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
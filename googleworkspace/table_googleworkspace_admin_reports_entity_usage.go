@@ -109,6 +109,35 @@ func tableGoogleWorkspaceAdminReportsEntityUsage(_ context.Context) *plugin.Tabl
 //// LIST FUNCTION
 
 func listAdminReportsEntityUsage(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	resp, err := newEntityUsageReportCall(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+
+	if err := resp.Pages(ctx, func(page *UsageReports) error {
+		for _, item := range page.UsageReports {
+			d.StreamListItem(ctx, item)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// newEntityUsageReportCall returns the call getting the entity usage report matching the quals of the query,
+// or nil if the query has no date
+func newEntityUsageReportCall(ctx context.Context, d *plugin.QueryData) (*EntityUsageReportsGetCall, error) {
 	// Create service
 	service, err := AdminReportsService(ctx, d)
 	if err != nil {
@@ -153,9 +182,7 @@ func listAdminReportsEntityUsage(ctx context.Context, d *plugin.QueryData, _ *pl
 		resp = resp.CustomerId(customer_id)
 	}
 
-	var parameters string
-	if d.KeyColumnQuals["parameters"] != nil {
-		parameters = d.KeyColumnQuals["parameters"].GetStringValue()
+	if parameters := usageReportParametersQual(d); parameters != "" {
 		resp = resp.Parameters(parameters)
 	}
 
@@ -165,20 +192,5 @@ func listAdminReportsEntityUsage(ctx context.Context, d *plugin.QueryData, _ *pl
 		resp = resp.Filters(filters)
 	}
 
-	if err := resp.Pages(ctx, func(page *UsageReports) error {
-		for _, item := range page.UsageReports {
-			d.StreamListItem(ctx, item)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if plugin.IsCancelled(ctx) {
-				page.NextPageToken = ""
-				break
-			}
-		}
-		return nil
-	}); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
+	return resp, nil
 }
@@ -0,0 +1,35 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceAdminReportsEntityUsageParameter(ctx context.Context) *plugin.Table {
+	return usageReportParameterTable(
+		tableGoogleWorkspaceAdminReportsEntityUsage(ctx),
+		"Retrieves the parameters of the usage reports of entities, one row per entity, date and parameter",
+		listAdminReportsEntityUsageParameters,
+	)
+}
+
+//// LIST FUNCTION
+
+func listAdminReportsEntityUsageParameters(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	call, err := newEntityUsageReportCall(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		return nil, nil
+	}
+
+	if err := listUsageReportParameters(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
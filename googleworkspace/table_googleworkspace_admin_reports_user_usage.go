@@ -119,6 +119,35 @@ func tableGoogleWorkspaceAdminReportsUserUsage(_ context.Context) *plugin.Table
 //// LIST FUNCTION
 
 func listAdminReportsUserUsage(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	resp, err := newUserUsageReportCall(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+
+	if err := resp.Pages(ctx, func(page *UsageReports) error {
+		for _, item := range page.UsageReports {
+			d.StreamListItem(ctx, item)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// newUserUsageReportCall returns the call getting the user usage report matching the quals of the query,
+// or nil if the query has no date
+func newUserUsageReportCall(ctx context.Context, d *plugin.QueryData) (*UserUsageReportGetCall, error) {
 	// Create service
 	service, err := AdminReportsService(ctx, d)
 	if err != nil {
@@ -156,9 +185,7 @@ func listAdminReportsUserUsage(ctx context.Context, d *plugin.QueryData, _ *plug
 		resp = resp.CustomerId(customer_id)
 	}
 
-	var parameters string
-	if d.KeyColumnQuals["parameters"] != nil {
-		parameters = d.KeyColumnQuals["parameters"].GetStringValue()
+	if parameters := usageReportParametersQual(d); parameters != "" {
 		resp = resp.Parameters(parameters)
 	}
 
@@ -180,20 +207,5 @@ func listAdminReportsUserUsage(ctx context.Context, d *plugin.QueryData, _ *plug
 		resp = resp.GroupIdFilter(group_id_filter)
 	}
 
-	if err := resp.Pages(ctx, func(page *UsageReports) error {
-		for _, item := range page.UsageReports {
-			d.StreamListItem(ctx, item)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if plugin.IsCancelled(ctx) {
-				page.NextPageToken = ""
-				break
-			}
-		}
-		return nil
-	}); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
+	return resp, nil
 }
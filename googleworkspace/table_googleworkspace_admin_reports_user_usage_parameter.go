@@ -0,0 +1,35 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceAdminReportsUserUsageParameter(ctx context.Context) *plugin.Table {
+	return usageReportParameterTable(
+		tableGoogleWorkspaceAdminReportsUserUsage(ctx),
+		"Retrieves the parameters of the usage reports of users, one row per user, date and parameter",
+		listAdminReportsUserUsageParameters,
+	)
+}
+
+//// LIST FUNCTION
+
+func listAdminReportsUserUsageParameters(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	call, err := newUserUsageReportCall(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		return nil, nil
+	}
+
+	if err := listUsageReportParameters(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
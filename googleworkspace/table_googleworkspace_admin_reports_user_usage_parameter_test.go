@@ -0,0 +1,93 @@
+package googleworkspace
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListAdminReportsUserUsageParameters(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsUserUsageParameter(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("date", stringValue("2022-02-01")),
+		},
+	})
+	if len(items) != 5 {
+		t.Fatalf("listed %d parameters, want 5", len(items))
+	}
+
+	columns := []string{"user_email", "parameter_name", "int_value", "bool_value", "datetime_value"}
+	want := []map[string]interface{}{
+		{"user_email": "jane@example.com", "parameter_name": "gmail:num_emails_sent", "int_value": int64(12), "bool_value": nil, "datetime_value": nil},
+		{"user_email": "jane@example.com", "parameter_name": "accounts:is_2sv_enrolled", "int_value": nil, "bool_value": true, "datetime_value": nil},
+		{"user_email": "jane@example.com", "parameter_name": "accounts:last_login_time", "int_value": nil, "bool_value": nil, "datetime_value": "2022-02-01T09:30:00.000Z"},
+		{"user_email": "john@example.com", "parameter_name": "gmail:num_emails_sent", "int_value": int64(3), "bool_value": nil, "datetime_value": nil},
+		{"user_email": "john@example.com", "parameter_name": "accounts:is_2sv_enrolled", "int_value": nil, "bool_value": false, "datetime_value": nil},
+	}
+	for i, item := range items {
+		if got := columnValues(t, table, item, columns...); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("row %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestListAdminReportsUserUsageParametersByName(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsUserUsageParameter(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("date", stringValue("2022-02-01")),
+			equalsQual("parameter_name", stringValue("gmail:num_emails_sent")),
+		},
+	})
+
+	// The recorded responses have every parameter, which the API would have left out
+	var counts []interface{}
+	for _, item := range items {
+		counts = append(counts, columnValues(t, table, item, "int_value")["int_value"])
+	}
+	if !reflect.DeepEqual(counts, []interface{}{int64(12), int64(3)}) {
+		t.Errorf("listed the emails sent %v, want [12 3]", counts)
+	}
+
+	request := server.requests("/admin/reports/v1/usage/users/all/dates/2022-02-01")[0]
+	if parameters := request.Query.Get("parameters"); parameters != "gmail:num_emails_sent" {
+		t.Errorf("parameters = %s, want gmail:num_emails_sent", parameters)
+	}
+}
+
+func TestUsageReportParameterValueOfEachType(t *testing.T) {
+	table := tableGoogleWorkspaceAdminReportsUserUsageParameter(context.Background())
+
+	msgValue := []interface{}{map[string]interface{}{"device_type": "android", "num_devices": "2"}}
+	parameters := []map[string]interface{}{
+		{"name": "gmail:num_emails_sent", "intValue": "12"},
+		{"name": "accounts:is_2sv_enrolled", "boolValue": false},
+		{"name": "accounts:last_login_time", "datetimeValue": "2022-02-01T09:30:00.000Z"},
+		{"name": "accounts:first_name", "stringValue": "Jane"},
+		{"name": "device_management:num_devices", "msgValue": msgValue},
+		{"name": "accounts:unknown"},
+	}
+
+	columns := []string{"value_type", "value", "int_value", "bool_value", "string_value"}
+	want := []map[string]interface{}{
+		{"value_type": "int", "value": int64(12), "int_value": int64(12), "bool_value": nil, "string_value": nil},
+		{"value_type": "bool", "value": false, "int_value": nil, "bool_value": false, "string_value": nil},
+		{"value_type": "datetime", "value": "2022-02-01T09:30:00.000Z", "int_value": nil, "bool_value": nil, "string_value": nil},
+		{"value_type": "string", "value": "Jane", "int_value": nil, "bool_value": nil, "string_value": "Jane"},
+		{"value_type": "msg", "value": msgValue, "int_value": nil, "bool_value": nil, "string_value": nil},
+		{"value_type": nil, "value": nil, "int_value": nil, "bool_value": nil, "string_value": nil},
+	}
+	for i, parameter := range parameters {
+		item := newUsageReportParameter(UsageReport{}, parameter)
+		if got := columnValues(t, table, item, columns...); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("%s = %v, want %v", parameter["name"], got, want[i])
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListAdminReportsUserUsage(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsUserUsage(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("date", stringValue("2022-02-01")),
+			equalsQual("parameters", stringValue("gmail:num_emails_sent")),
+		},
+	})
+
+	var emails []string
+	for _, item := range items {
+		emails = append(emails, item.(*UsageReport).Entity.UserEmail)
+	}
+	if len(emails) != 2 || emails[0] != "jane@example.com" || emails[1] != "john@example.com" {
+		t.Errorf("listed the usage of %v, want jane@example.com and john@example.com", emails)
+	}
+
+	requests := server.requests("/admin/reports/v1/usage/users/all/dates/2022-02-01")
+	if len(requests) != 2 {
+		t.Fatalf("listed %d pages, want 2", len(requests))
+	}
+	if parameters := requests[0].Query.Get("parameters"); parameters != "gmail:num_emails_sent" {
+		t.Errorf("parameters = %s, want gmail:num_emails_sent", parameters)
+	}
+}
+
+func TestListAdminReportsUserUsageRequiresDate(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceAdminReportsUserUsage(context.Background())
+
+	if items := listRows(t, table, server.connection(), testQuery{}); len(items) != 0 {
+		t.Errorf("listed %d usage reports without a date, want none", len(items))
+	}
+	if len(server.requests("/admin/reports/v1/usage/users/all/dates/2022-02-01")) != 0 {
+		t.Error("requested usage reports without a date")
+	}
+}
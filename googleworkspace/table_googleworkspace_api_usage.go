@@ -0,0 +1,117 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceAPIUsage(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_api_usage",
+		Description: "The HTTP calls made to the Google Workspace APIs by the queries of the connection, since the plugin started.",
+		List: &plugin.ListConfig{
+			Hydrate: listAPIUsage,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "api",
+				Description: "The API called, e.g. admin_reports, calendar, directory, drive, gmail or people.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("API"),
+			},
+			{
+				Name:        "table_name",
+				Description: "The table whose queries made the calls.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Table"),
+			},
+			{
+				Name:        "api_method",
+				Description: "The API method called, e.g. users.list or users.messages.get, as named in the API reference. Calls to other methods are accounted under their HTTP method.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("APIMethod"),
+			},
+			{
+				Name:        "call_count",
+				Description: "The number of calls, including retries.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("CallCount"),
+			},
+			{
+				Name:        "retry_count",
+				Description: "The number of calls which retried a rate limited or failed call.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("RetryCount"),
+			},
+			{
+				Name:        "rate_limited_count",
+				Description: "The number of calls which were rate limited by the API, with a 429 status.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("RateLimitedCount"),
+			},
+			{
+				Name:        "error_count",
+				Description: "The number of calls which failed, or returned an error status.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("ErrorCount"),
+			},
+			{
+				Name:        "bytes_sent",
+				Description: "The number of bytes sent in the bodies of the requests.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("BytesSent"),
+			},
+			{
+				Name:        "bytes_received",
+				Description: "The number of bytes received in the bodies of the responses.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("BytesReceived"),
+			},
+			{
+				Name:        "average_latency_ms",
+				Description: "The average time the calls took until the response headers were received, in milliseconds.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("AverageLatencyMs"),
+			},
+			{
+				Name:        "max_latency_ms",
+				Description: "The longest time a call took until the response headers were received, in milliseconds.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("MaxLatencyMs"),
+			},
+			{
+				Name:        "first_call_time",
+				Description: "The time of the first call.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("FirstCallTime"),
+			},
+			{
+				Name:        "last_call_time",
+				Description: "The time of the last call.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("LastCallTime"),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listAPIUsage(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// The usage is accounted by the plugin itself, so no API is called
+	for _, usage := range apiUsageOfConnection(connectionName(d)) {
+		d.StreamListItem(ctx, usage)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if plugin.IsCancelled(ctx) {
+			break
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+package googleworkspace
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListAPIUsageAccountsCallsPerTable(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	connection := server.connection()
+
+	listRows(t, tableGoogleWorkspaceDrive(context.Background()), connection, testQuery{columns: []string{"id", "name"}})
+
+	items := listRows(t, tableGoogleWorkspaceAPIUsage(context.Background()), connection, testQuery{})
+	if len(items) != 1 {
+		t.Fatalf("listed %d usages, want 1: %v", len(items), items)
+	}
+
+	usage := items[0].(apiUsage)
+	if usage.API != "drive" || usage.Table != "googleworkspace_drive" || usage.APIMethod != "drives.list" {
+		t.Errorf("usage of %s.%s by %s, want drive.drives.list by googleworkspace_drive", usage.API, usage.APIMethod, usage.Table)
+	}
+	if calls := int64(len(server.requests("/drive/v3/drives"))); usage.CallCount != calls {
+		t.Errorf("call_count = %d, want %d", usage.CallCount, calls)
+	}
+	if usage.BytesReceived == 0 {
+		t.Error("bytes_received = 0, want the size of the responses")
+	}
+	if usage.RetryCount != 0 || usage.RateLimitedCount != 0 || usage.ErrorCount != 0 {
+		t.Errorf("retries, rate limits and errors = %d, %d, %d, want none", usage.RetryCount, usage.RateLimitedCount, usage.ErrorCount)
+	}
+}
+
+func TestUsageTransportCountsRetriesAndRateLimits(t *testing.T) {
+	key := apiUsageKey{Connection: t.Name(), API: "drive", Table: "googleworkspace_drive", APIMethod: "GET"}
+	client, server, _ := newRetryTestClient(t,
+		respond(http.StatusTooManyRequests, ""),
+		respond(http.StatusOK, "ok"),
+	)
+	transport := client.Transport.(*retryTransport)
+	transport.base = &usageTransport{base: transport.base, connection: key.Connection, api: key.API, table: key.Table}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.ReadAll(resp.Body)
+
+	usage := apiUsageOfConnection(key.Connection)
+	if len(usage) != 1 {
+		t.Fatalf("got %d usages, want 1", len(usage))
+	}
+	if usage[0].CallCount != 2 || usage[0].RetryCount != 1 || usage[0].RateLimitedCount != 1 || usage[0].ErrorCount != 1 {
+		t.Errorf("calls, retries, rate limits and errors = %d, %d, %d, %d, want 2, 1, 1, 1", usage[0].CallCount, usage[0].RetryCount, usage[0].RateLimitedCount, usage[0].ErrorCount)
+	}
+	if usage[0].BytesReceived != 2 {
+		t.Errorf("bytes_received = %d, want 2", usage[0].BytesReceived)
+	}
+}
+
+func TestListAPIUsageThroughEndpointWithPathPrefix(t *testing.T) {
+	server := newReplayServer(t, "drive")
+
+	// The endpoint is behind a proxy, which serves the API under a path of its own
+	proxy := httptest.NewServer(http.StripPrefix("/proxy", server.Config.Handler))
+	defer proxy.Close()
+	connection := server.connection()
+	config := connection.Config.(googleworkspaceConfig)
+	driveEndpoint := proxy.URL + "/proxy/drive/v3/"
+	config.DriveEndpoint = &driveEndpoint
+	connection.Config = config
+
+	listRows(t, tableGoogleWorkspaceDrive(context.Background()), connection, testQuery{columns: []string{"id", "name"}})
+
+	items := listRows(t, tableGoogleWorkspaceAPIUsage(context.Background()), connection, testQuery{})
+	if len(items) != 1 {
+		t.Fatalf("listed %d usages, want 1: %v", len(items), items)
+	}
+	if method := items[0].(apiUsage).APIMethod; method != "drives.list" {
+		t.Errorf("api_method = %s, want drives.list", method)
+	}
+}
+
+func TestAPIMethod(t *testing.T) {
+	tests := []struct {
+		request  string
+		api      string
+		endpoint string
+		want     string
+	}{
+		{"GET /admin/directory/v1/users", "directory", "", "users.list"},
+		{"GET /admin/directory/v1/users/jane@example.com", "directory", "", "users.get"},
+		{"GET /admin/directory/v1/customer/my_customer/roles/ALL/privileges", "directory", "", "privileges.list"},
+		{"GET /admin/directory/v1/customer/my_customer/roles/101", "directory", "", "roles.get"},
+		{"GET /admin/directory/v1/customer/my_customer/orgunits/sales/emea", "directory", "", "orgunits.get"},
+		{"GET /admin/reports/v1/activity/users/all/applications/login", "admin_reports", "", "activities.list"},
+		{"GET /admin/reports/v1/usage/users/all/dates/2022-01-31", "admin_reports", "", "userUsageReport.get"},
+		{"GET /admin/reports/v1/usage/gplus_communities/all/dates/2022-01-31", "admin_reports", "", "entityUsageReports.get"},
+		{"GET /gmail/v1/users/me/messages/message-1", "gmail", "", "users.messages.get"},
+		{"GET /calendar/v3/calendars/primary/events", "calendar", "", "events.list"},
+		{"GET /v1/people:listDirectoryPeople", "people", "", "people.listDirectoryPeople"},
+		{"POST /admin/directory/v1/users", "directory", "", "POST"},
+		{"GET /unknown", "directory", "", "GET"},
+
+		// The paths are relative to the configured endpoint
+		{"GET /google/admin/directory/v1/users", "directory", "https://proxy.example.com/google/", "users.list"},
+		{"GET /google/calendar/v3/calendars/primary/events", "calendar", "https://proxy.example.com/google/calendar/v3", "events.list"},
+		{"GET /drive/v3/files", "drive", "https://proxy.example.com/google/drive/v3/", "GET"},
+	}
+
+	for _, test := range tests {
+		fields := strings.Fields(test.request)
+		req, err := http.NewRequest(fields[0], "https://example.com"+fields[1], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var config googleworkspaceConfig
+		switch test.api {
+		case "calendar":
+			config.CalendarEndpoint = &test.endpoint
+		case "directory":
+			config.DirectoryEndpoint = &test.endpoint
+		case "drive":
+			config.DriveEndpoint = &test.endpoint
+		}
+		if got := apiMethod(req, test.api, apiBasePath(config, test.api)); got != test.want {
+			t.Errorf("apiMethod(%s) of %s = %s, want %s", test.request, test.endpoint, got, test.want)
+		}
+	}
+}
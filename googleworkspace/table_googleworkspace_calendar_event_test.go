@@ -0,0 +1,25 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestGetCalendarEvent(t *testing.T) {
+	server := newReplayServer(t, "calendar")
+	table := tableGoogleWorkspaceCalendarEvent(context.Background())
+
+	item := getRow(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("calendar_id", stringValue("team@example.com")),
+			equalsQual("id", stringValue("event-4")),
+		},
+	})
+
+	event := item.(calendarEvent)
+	if event.Summary != "Planning" || event.CalendarId != "team@example.com" {
+		t.Errorf("got %s of %s, want Planning of team@example.com", event.Summary, event.CalendarId)
+	}
+}
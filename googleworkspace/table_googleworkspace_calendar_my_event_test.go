@@ -0,0 +1,64 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListCalendarMyEventsPushesDownStartTime(t *testing.T) {
+	server := newReplayServer(t, "calendar")
+	table := tableGoogleWorkspaceCalendarMyEvent(context.Background())
+
+	listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("query", stringValue("standup")),
+			{Column: "start_time", Operator: ">", Value: timestampValue("2022-02-01T00:00:00Z")},
+			{Column: "start_time", Operator: "<=", Value: timestampValue("2022-02-28T00:00:00Z")},
+		},
+	})
+
+	request := server.requests("/calendar/v3/calendars/primary/events")[0]
+	want := map[string]string{
+		"q":            "standup",
+		"timeMin":      "2022-02-01T00:00:01.000Z",
+		"timeMax":      "2022-02-28T00:00:00.000Z",
+		"singleEvents": "true",
+		"showDeleted":  "false",
+	}
+	for name, value := range want {
+		if got := request.Query.Get(name); got != value {
+			t.Errorf("%s = %s, want %s", name, got, value)
+		}
+	}
+}
+
+func TestListCalendarMyEventsPaginatesUntilLimit(t *testing.T) {
+	server := newReplayServer(t, "calendar")
+	table := tableGoogleWorkspaceCalendarMyEvent(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{})
+	if len(items) != 3 {
+		t.Errorf("listed %d events, want 3", len(items))
+	}
+	if event := items[0].(calendarEvent); event.CalendarId != "jane@example.com" {
+		t.Errorf("calendar_id = %s, want the summary of the primary calendar", event.CalendarId)
+	}
+	if pages := len(server.requests("/calendar/v3/calendars/primary/events")); pages != 2 {
+		t.Errorf("listed %d pages, want 2", pages)
+	}
+
+	server = newReplayServer(t, "calendar")
+	items = listRows(t, table, server.connection(), testQuery{limit: limit(2)})
+	if len(items) != 2 {
+		t.Errorf("listed %d events, want 2", len(items))
+	}
+	requests := server.requests("/calendar/v3/calendars/primary/events")
+	if len(requests) != 1 {
+		t.Fatalf("listed %d pages, want 1", len(requests))
+	}
+	if maxResults := requests[0].Query.Get("maxResults"); maxResults != "2" {
+		t.Errorf("maxResults = %s, want the limit", maxResults)
+	}
+}
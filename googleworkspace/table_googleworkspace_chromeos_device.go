@@ -0,0 +1,304 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceChromeOSDevice(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_chromeos_device",
+		Description: "Chrome OS devices managed in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listChromeOSDevices,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "query",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "org_unit_path",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "projection",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("device_id"),
+			Hydrate:    getChromeOSDevice,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "device_id",
+				Description: "The unique ID of the Chrome device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "serial_number",
+				Description: "The Chrome device serial number entered when the device was enabled.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "The status of the device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "model",
+				Description: "The device's model information.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "org_unit_path",
+				Description: "The full parent path with the organizational unit's name associated with the device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "os_version",
+				Description: "The Chrome device's operating system version.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "platform_version",
+				Description: "The Chrome device's platform version.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "firmware_version",
+				Description: "The Chrome device's firmware version.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "boot_mode",
+				Description: "The boot mode for the device. Possible values are: Verified and Dev.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "last_sync",
+				Description: "The date and time the device was last synchronized with the policy settings in the Admin console.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "last_enrollment_time",
+				Description: "The date and time the device was last enrolled.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "auto_update_expiration",
+				Description: "The date at which the device will stop receiving automatic updates.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("AutoUpdateExpiration").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "support_end_date",
+				Description: "The final date the device will receive support.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "will_auto_renew",
+				Description: "Indicates whether the device's support contract will auto renew, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("WillAutoRenew"),
+			},
+			{
+				Name:        "annotated_user",
+				Description: "The user of the device as noted by the administrator.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "annotated_asset_id",
+				Description: "The asset identifier as noted by an administrator or specified during enrollment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "annotated_location",
+				Description: "The address or location of the device as noted by the administrator.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "notes",
+				Description: "Notes about this device added by the administrator.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "mac_address",
+				Description: "The device's wireless MAC address.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "ethernet_mac_address",
+				Description: "The device's MAC address on the ethernet network interface.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "meid",
+				Description: "The Mobile Equipment Identifier (MEID) or the International Mobile Equipment Identity (IMEI) for the 3G mobile card in a mobile device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "order_number",
+				Description: "The device's order number.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "system_ram_total",
+				Description: "The total RAM on the device in bytes.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "query",
+				Description: "A query string for [searching](https://developers.google.com/admin-sdk/directory/v1/list-query-operators) Chrome OS device fields.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("query"),
+			},
+			{
+				Name:        "projection",
+				Description: "Restricts the information returned to a set of selected fields. Possible values are: BASIC and FULL. Defaults to FULL.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("projection"),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "owner_emails",
+				Description: "The email addresses of the users recently signed in to the device, followed by the annotated user.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.From(chromeOSDeviceOwnerEmails),
+			},
+			{
+				Name:        "recent_users",
+				Description: "A list of recent device users, in descending order, by last login time.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "active_time_ranges",
+				Description: "A list of active time ranges.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "last_known_network",
+				Description: "Contains last known network.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "tpm_version_info",
+				Description: "Trusted Platform Module (TPM) information.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listChromeOSDevices(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 300 records in a single page
+	maxResult := int64(300)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	// The recent users of the device are only returned in the FULL projection
+	projection := "FULL"
+	if d.KeyColumnQuals["projection"] != nil {
+		projection = d.KeyColumnQuals["projection"].GetStringValue()
+	}
+
+	resp := service.Chromeosdevices.List("my_customer").Projection(projection).MaxResults(maxResult)
+
+	// Query string for searching Chrome OS device fields. Refer https://developers.google.com/admin-sdk/directory/v1/list-query-operators
+	// For example, "status:provisioned"
+	if d.KeyColumnQuals["query"] != nil {
+		resp = resp.Query(d.KeyColumnQuals["query"].GetStringValue())
+	}
+
+	if d.KeyColumnQuals["org_unit_path"] != nil {
+		resp = resp.OrgUnitPath(d.KeyColumnQuals["org_unit_path"].GetStringValue())
+	}
+
+	if err := resp.Pages(ctx, func(page *admin.ChromeOsDevices) error {
+		for _, device := range page.Chromeosdevices {
+			d.StreamListItem(ctx, device)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getChromeOSDevice(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	deviceID := d.KeyColumnQuals["device_id"].GetStringValue()
+
+	// Return nil, if no input provided
+	if deviceID == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Chromeosdevices.Get("my_customer", deviceID).Projection("FULL").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func chromeOSDeviceOwnerEmails(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	device := d.HydrateItem.(*admin.ChromeOsDevice)
+
+	var emails []string
+	seen := map[string]bool{}
+	for _, user := range device.RecentUsers {
+		if user.Email != "" && !seen[user.Email] {
+			seen[user.Email] = true
+			emails = append(emails, user.Email)
+		}
+	}
+	if device.AnnotatedUser != "" && !seen[device.AnnotatedUser] {
+		emails = append(emails, device.AnnotatedUser)
+	}
+
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	return emails, nil
+}
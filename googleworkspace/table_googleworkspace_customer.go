@@ -0,0 +1,95 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceCustomer(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_customer",
+		Description: "Profile of the Google Workspace customer, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listCustomers,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "customer_id",
+				Description: "The unique ID for the customer's Google Workspace account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id"),
+			},
+			{
+				Name:        "customer_domain",
+				Description: "The customer's primary domain name.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "alternate_email",
+				Description: "The customer's secondary contact email address.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "customer_creation_time",
+				Description: "The customer's creation time.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "language",
+				Description: "The customer's ISO 639-2 language code.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "phone_number",
+				Description: "The customer's contact phone number in E.164 format.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "organization_name",
+				Description: "The company or company division name.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PostalAddress.OrganizationName"),
+			},
+			{
+				Name:        "country_code",
+				Description: "The country code of the customer's postal address.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PostalAddress.CountryCode"),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "postal_address",
+				Description: "The customer's postal address information.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listCustomers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The authenticated user can only retrieve the customer account they belong to
+	resp, err := service.Customers.Get("my_customer").Do()
+	if err != nil {
+		return nil, err
+	}
+	d.StreamListItem(ctx, resp)
+
+	return nil, nil
+}
@@ -0,0 +1,110 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceDomain(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_domain",
+		Description: "Domains of the Google Workspace customer, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listDomains,
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("domain_name"),
+			Hydrate:    getDomain,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "domain_name",
+				Description: "The domain name of the customer.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "is_primary",
+				Description: "Indicates whether the domain is the primary domain of the customer, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("IsPrimary"),
+			},
+			{
+				Name:        "verified",
+				Description: "Indicates whether the domain is verified, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Verified"),
+			},
+			{
+				Name:        "creation_time",
+				Description: "The time when the domain was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CreationTime").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "domain_aliases",
+				Description: "A list of domain alias objects.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listDomains(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API doesn't paginate, and returns every domain in a single response
+	resp, err := service.Domains.List("my_customer").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, domain := range resp.Domains {
+		d.StreamListItem(ctx, domain)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if plugin.IsCancelled(ctx) {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getDomain(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	domainName := d.KeyColumnQuals["domain_name"].GetStringValue()
+
+	// Return nil, if no input provided
+	if domainName == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Domains.Get("my_customer", domainName).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,115 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceDomainAlias(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_domain_alias",
+		Description: "Domain aliases of the Google Workspace customer, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listDomainAliases,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "parent_domain_name",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("domain_alias_name"),
+			Hydrate:    getDomainAlias,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "domain_alias_name",
+				Description: "The domain alias name.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "parent_domain_name",
+				Description: "The parent domain name that the domain alias is associated with.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "verified",
+				Description: "Indicates whether the domain alias is verified, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Verified"),
+			},
+			{
+				Name:        "creation_time",
+				Description: "The time when the domain alias was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("CreationTime").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listDomainAliases(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := service.DomainAliases.List("my_customer")
+	if d.KeyColumnQuals["parent_domain_name"] != nil {
+		resp = resp.ParentDomainName(d.KeyColumnQuals["parent_domain_name"].GetStringValue())
+	}
+
+	// The API doesn't paginate, and returns every domain alias in a single response
+	aliases, err := resp.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases.DomainAliases {
+		d.StreamListItem(ctx, alias)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if plugin.IsCancelled(ctx) {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getDomainAlias(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	aliasName := d.KeyColumnQuals["domain_alias_name"].GetStringValue()
+
+	// Return nil, if no input provided
+	if aliasName == "" {
+		return nil, nil
+	}
+
+	resp, err := service.DomainAliases.Get("my_customer", aliasName).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
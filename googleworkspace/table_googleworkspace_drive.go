@@ -244,9 +244,10 @@ func getDrive(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 
 	// Check for query context and requests only for queried columns
 	givenColumns := d.QueryContext.Columns
-	requiredFields := buildDriveRequestFields(ctx, givenColumns)
+	// Unlike the list, the response is the drive itself
+	requiredFields := googleapi.Field(buildDriveFields(ctx, givenColumns))
 
-	resp, err := service.Drives.Get(id).Fields(requiredFields...).Do()
+	resp, err := service.Drives.Get(id).Fields(requiredFields).Do()
 	if err != nil {
 		return nil, err
 	}
@@ -256,9 +257,18 @@ func getDrive(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 
 // buildDriveRequestFields :: Return columns passed in query context
 func buildDriveRequestFields(ctx context.Context, queryColumns []string) []googleapi.Field {
-	var fields []string
 	var requestedFields []googleapi.Field
 
+	givenFields := buildDriveFields(ctx, queryColumns)
+	requestedFields = append(requestedFields, googleapi.Field(fmt.Sprintf("nextPageToken, drives(%s)", givenFields)))
+
+	return requestedFields
+}
+
+// buildDriveFields :: Return the drive fields of the columns passed in query context
+func buildDriveFields(_ context.Context, queryColumns []string) string {
+	var fields []string
+
 	for _, columnName := range queryColumns {
 		// Optional columns
 		if columnName == "query" || columnName == "use_domain_admin_access" {
@@ -274,8 +284,5 @@ func buildDriveRequestFields(ctx context.Context, queryColumns []string) []googl
 		}
 	}
 
-	givenFields := strings.Join(fields, ", ")
-	requestedFields = append(requestedFields, googleapi.Field(fmt.Sprintf("nextPageToken, drives(%s)", givenFields)))
-
-	return requestedFields
+	return strings.Join(fields, ", ")
 }
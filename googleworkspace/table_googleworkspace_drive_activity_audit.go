@@ -0,0 +1,211 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+// The columns of the Drive audit table which are parameters of the events, and can be filtered on by the API
+var driveAuditFilterColumns = []string{"doc_id", "doc_title", "doc_type", "owner", "visibility", "old_visibility", "target_user"}
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceDriveActivityAudit(_ context.Context) *plugin.Table {
+	keyColumns := []*plugin.KeyColumn{
+		{
+			Name:    "user_key",
+			Require: plugin.Optional,
+		},
+		{
+			Name:      "time",
+			Require:   plugin.Optional,
+			Operators: []string{">", ">=", "=", "<", "<="},
+		},
+		{
+			Name:    "event_name",
+			Require: plugin.Optional,
+		},
+	}
+	for _, column := range driveAuditFilterColumns {
+		keyColumns = append(keyColumns, &plugin.KeyColumn{
+			Name:      column,
+			Require:   plugin.Optional,
+			Operators: []string{"=", "<>", "!="},
+		})
+	}
+
+	return &plugin.Table{
+		Name:        "googleworkspace_drive_activity_audit",
+		Description: "Drive events of the users of the Google Workspace account, such as views, edits and sharing changes, from the Drive activity report.",
+		List: &plugin.ListConfig{
+			Hydrate:    listDriveActivityAudits,
+			KeyColumns: keyColumns,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "time",
+				Description: "The time of the Drive event.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Id.Time"),
+			},
+			{
+				Name:        "event_name",
+				Description: "The name of the Drive event, e.g. view, edit, download or change_document_visibility.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Name"),
+			},
+			{
+				Name:        "event_type",
+				Description: "The type of the Drive event, e.g. access or acl_change.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Type"),
+			},
+			{
+				Name:        "email",
+				Description: "The primary email address of the user who performed the event.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.Email"),
+			},
+			{
+				Name:        "doc_id",
+				Description: "The ID of the Drive item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.doc_id"),
+			},
+			{
+				Name:        "doc_title",
+				Description: "The title of the Drive item.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.doc_title"),
+			},
+			{
+				Name:        "doc_type",
+				Description: "The type of the Drive item, e.g. document, spreadsheet, folder or pdf.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.doc_type"),
+			},
+			{
+				Name:        "owner",
+				Description: "The email address of the owner of the Drive item, or the name of its shared drive.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.owner"),
+			},
+			{
+				Name:        "visibility",
+				Description: "The visibility of the Drive item after the event, e.g. private, shared_internally, shared_externally, people_with_link or public_on_the_web.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.visibility"),
+			},
+			{
+				Name:        "old_visibility",
+				Description: "The visibility of the Drive item before a visibility change.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.old_visibility"),
+			},
+			{
+				Name:        "target_user",
+				Description: "The email address of the user whose access to the Drive item was changed.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.target_user"),
+			},
+			{
+				Name:        "target_domain",
+				Description: "The domain whose access to the Drive item was changed.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.target_domain"),
+			},
+			{
+				Name:        "acl_change_type",
+				Description: "The kind of access change, for acl_change events, e.g. change_user_access, change_document_access_scope or change_document_visibility.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(driveAuditACLChangeType),
+			},
+			{
+				Name:        "visibility_change",
+				Description: "Whether the access change grants access inside or outside the domain, i.e. internal or external.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.visibility_change"),
+			},
+			{
+				Name:        "old_value",
+				Description: "The roles or access scopes before an access change.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters.old_value"),
+			},
+			{
+				Name:        "new_value",
+				Description: "The roles or access scopes after an access change.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters.new_value"),
+			},
+			{
+				Name:        "primary_event",
+				Description: "Indicates whether the event was performed by the user directly, rather than as a side effect of another event, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Parameters.primary_event"),
+			},
+			{
+				Name:        "parameters",
+				Description: "All the parameters of the Drive event by name, with plain JSON values.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters"),
+			},
+			{
+				Name:        "user_key",
+				Description: "The email address or profile ID of the user to list the Drive events of, as given in the where clause. Otherwise, the email address of the user who performed the event, or the unique identifier of the actor if it isn't a user.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor").Transform(activityUserKey),
+			},
+			{
+				Name:        "actor_ip_address",
+				Description: "The IP address of the user who performed the event.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("IpAddress"),
+			},
+			{
+				Name:        "unique_qualifier",
+				Description: "Unique qualifier of the activity the event belongs to, if multiple activities have the same time.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.UniqueQualifier"),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listDriveActivityAudits(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	call, err := newActivitiesListCall(ctx, d, "drive")
+	if err != nil {
+		return nil, err
+	}
+
+	// The quals on the time column can't match any activity
+	if call == nil {
+		return nil, nil
+	}
+
+	if filters := activityParameterFilters(d, driveAuditFilterColumns); filters != "" {
+		call.Filters(filters)
+	}
+
+	if err := listActivityEvents(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+// The events of an access change are named after the kind of change
+func driveAuditACLChangeType(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	event := d.HydrateItem.(activityEvent)
+	if event.Event == nil || event.Event.Type != "acl_change" {
+		return nil, nil
+	}
+	return event.Event.Name, nil
+}
@@ -0,0 +1,63 @@
+package googleworkspace
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListDriveActivityAuditsPushesDownFilters(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceDriveActivityAudit(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("doc_id", stringValue("doc-1")),
+			{Column: "visibility", Operator: "<>", Value: stringValue("private")},
+			equalsQual("target_user", stringValue("partner@example.org,other@example.org")),
+		},
+	})
+	if len(items) != 2 {
+		t.Fatalf("listed %d events, want 2", len(items))
+	}
+
+	// The value containing a comma is left for Steampipe to filter on
+	request := server.requests("/admin/reports/v1/activity/users/all/applications/drive")[0]
+	if filters := request.Query.Get("filters"); filters != "doc_id==doc-1,visibility<>private" {
+		t.Errorf("filters = %s, want doc_id==doc-1,visibility<>private", filters)
+	}
+}
+
+func TestListDriveActivityAuditsTypesSharingChanges(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceDriveActivityAudit(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{})
+	if len(items) != 2 {
+		t.Fatalf("listed %d events, want 2", len(items))
+	}
+
+	columns := []string{"user_key", "doc_id", "doc_title", "doc_type", "owner", "visibility", "old_visibility", "target_user", "acl_change_type", "visibility_change"}
+	want := map[string]interface{}{
+		"user_key":          "jane@example.com",
+		"doc_id":            "doc-1",
+		"doc_title":         "Roadmap",
+		"doc_type":          "document",
+		"owner":             "jane@example.com",
+		"visibility":        "shared_externally",
+		"old_visibility":    "people_within_domain_with_link",
+		"target_user":       "partner@example.org",
+		"acl_change_type":   "change_user_access",
+		"visibility_change": "external",
+	}
+	if got := columnValues(t, table, items[1], columns...); !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
+
+	// Events which don't change access have no ACL change type
+	if got := columnValues(t, table, items[0], "acl_change_type"); got["acl_change_type"] != nil {
+		t.Errorf("acl_change_type of an edit = %v, want null", got["acl_change_type"])
+	}
+}
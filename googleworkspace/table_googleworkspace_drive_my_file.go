@@ -462,11 +462,11 @@ func getDriveMyFile(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 	}
 
 	// Check for query context and requests only for queried columns
+	// Unlike the list, the response is the file itself
 	givenColumns := d.QueryContext.Columns
-	requiredFields := buildDriveFileRequestFields(ctx, givenColumns)
+	requiredFields := googleapi.Field(buildDriveFileFields(ctx, givenColumns))
 
-	// Use "*" to return all fields
-	resp, err := service.Files.Get(fileID).Fields(requiredFields...).Do()
+	resp, err := service.Files.Get(fileID).Fields(requiredFields).Do()
 	if err != nil {
 		return nil, err
 	}
@@ -476,9 +476,18 @@ func getDriveMyFile(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 
 // buildDriveFileRequestFields :: Return columns passed in query context
 func buildDriveFileRequestFields(ctx context.Context, queryColumns []string) []googleapi.Field {
-	var fields []string
 	var requestedFields []googleapi.Field
 
+	givenFields := buildDriveFileFields(ctx, queryColumns)
+	requestedFields = append(requestedFields, googleapi.Field(fmt.Sprintf("nextPageToken, files(%s)", givenFields)))
+
+	return requestedFields
+}
+
+// buildDriveFileFields :: Return the file fields of the columns passed in query context
+func buildDriveFileFields(_ context.Context, queryColumns []string) string {
+	var fields []string
+
 	for _, columnName := range queryColumns {
 		// Optional columns
 		if columnName == "query" {
@@ -493,8 +502,5 @@ func buildDriveFileRequestFields(ctx context.Context, queryColumns []string) []g
 		}
 	}
 
-	givenFields := strings.Join(fields, ", ")
-	requestedFields = append(requestedFields, googleapi.Field(fmt.Sprintf("nextPageToken, files(%s)", givenFields)))
-
-	return requestedFields
+	return strings.Join(fields, ", ")
 }
@@ -0,0 +1,114 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+	"google.golang.org/api/drive/v3"
+)
+
+func TestListDriveMyFilesPushesDownQuals(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	table := tableGoogleWorkspaceDriveMyFile(context.Background())
+
+	listRows(t, table, server.connection(), testQuery{
+		columns: []string{"id", "name", "original_file_name"},
+		quals: []*quals.Qual{
+			equalsQual("name", stringValue("Quarterly report")),
+			{Column: "created_time", Operator: ">=", Value: timestampValue("2022-02-01T00:00:00Z")},
+			{Column: "mime_type", Operator: "<>", Value: stringValue("application/vnd.google-apps.folder")},
+		},
+	})
+
+	requests := server.requests("/drive/v3/files")
+	if len(requests) == 0 {
+		t.Fatal("no files were listed")
+	}
+
+	wantQ := `name = "Quarterly report" and createdTime > "2022-01-31T23:59:59.000Z" and mimeType != "application/vnd.google-apps.folder"`
+	if q := requests[0].Query.Get("q"); q != wantQ {
+		t.Errorf("q = %s, want %s", q, wantQ)
+	}
+
+	wantFields := "nextPageToken, files(id, name, originalFilename)"
+	if fields := requests[0].Query.Get("fields"); fields != wantFields {
+		t.Errorf("fields = %s, want %s", fields, wantFields)
+	}
+}
+
+func TestListDriveMyFilesQueryOverridesQuals(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	table := tableGoogleWorkspaceDriveMyFile(context.Background())
+
+	listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("name", stringValue("Quarterly report")),
+			equalsQual("query", stringValue("name contains 'report'")),
+		},
+	})
+
+	if q := server.requests("/drive/v3/files")[0].Query.Get("q"); q != "name contains 'report'" {
+		t.Errorf("q = %s, want the query qual", q)
+	}
+}
+
+func TestListDriveMyFilesPaginates(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	table := tableGoogleWorkspaceDriveMyFile(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{})
+
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.(*drive.File).Id)
+	}
+	if len(ids) != 3 || ids[0] != "file-1" || ids[2] != "file-3" {
+		t.Errorf("listed files %v, want file-1, file-2 and file-3", ids)
+	}
+
+	requests := server.requests("/drive/v3/files")
+	if len(requests) != 2 {
+		t.Fatalf("listed %d pages, want 2", len(requests))
+	}
+	if pageSize := requests[0].Query.Get("pageSize"); pageSize != "1000" {
+		t.Errorf("pageSize = %s, want 1000", pageSize)
+	}
+}
+
+func TestListDriveMyFilesStopsAtLimit(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	table := tableGoogleWorkspaceDriveMyFile(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{limit: limit(1)})
+	if len(items) != 1 {
+		t.Errorf("listed %d files, want 1", len(items))
+	}
+
+	// The next page must not be requested once the limit has been hit
+	requests := server.requests("/drive/v3/files")
+	if len(requests) != 1 {
+		t.Fatalf("listed %d pages, want 1", len(requests))
+	}
+	if pageSize := requests[0].Query.Get("pageSize"); pageSize != "1" {
+		t.Errorf("pageSize = %s, want the limit", pageSize)
+	}
+}
+
+func TestGetDriveMyFile(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	table := tableGoogleWorkspaceDriveMyFile(context.Background())
+
+	item := getRow(t, table, server.connection(), testQuery{
+		columns: []string{"id", "name"},
+		quals:   []*quals.Qual{equalsQual("id", stringValue("file-1"))},
+	})
+	if file := item.(*drive.File); file.Name != "Quarterly report" {
+		t.Errorf("name = %s, want Quarterly report", file.Name)
+	}
+
+	// The fields of a single file aren't wrapped in the list's fields
+	if fields := server.requests("/drive/v3/files/file-1")[0].Query.Get("fields"); fields != "id, name" {
+		t.Errorf("fields = %s, want id, name", fields)
+	}
+}
@@ -0,0 +1,55 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+	"google.golang.org/api/drive/v3"
+)
+
+func TestListDrivesPushesDownQuals(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	table := tableGoogleWorkspaceDrive(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		columns: []string{"id", "name", "domain_users_only"},
+		quals: []*quals.Qual{
+			equalsQual("name", stringValue("Engineering")),
+			equalsQual("use_domain_admin_access", &proto.QualValue{Value: &proto.QualValue_BoolValue{BoolValue: true}}),
+		},
+	})
+	if len(items) != 1 || items[0].(*drive.Drive).Id != "drive-1" {
+		t.Errorf("listed %v, want drive-1", items)
+	}
+
+	request := server.requests("/drive/v3/drives")[0]
+	if q := request.Query.Get("q"); q != `name = "Engineering"` {
+		t.Errorf("q = %s, want the name filter", q)
+	}
+	if useDomainAdminAccess := request.Query.Get("useDomainAdminAccess"); useDomainAdminAccess != "true" {
+		t.Errorf("useDomainAdminAccess = %s, want true", useDomainAdminAccess)
+	}
+	if fields := request.Query.Get("fields"); fields != "nextPageToken, drives(id, name, restrictions/domainUsersOnly)" {
+		t.Errorf("fields = %s, want the drives fields of the columns", fields)
+	}
+}
+
+func TestGetDrive(t *testing.T) {
+	server := newReplayServer(t, "drive")
+	table := tableGoogleWorkspaceDrive(context.Background())
+
+	item := getRow(t, table, server.connection(), testQuery{
+		columns: []string{"id", "name", "domain_users_only"},
+		quals:   []*quals.Qual{equalsQual("id", stringValue("drive-1"))},
+	})
+	if sharedDrive := item.(*drive.Drive); !sharedDrive.Restrictions.DomainUsersOnly {
+		t.Error("domain_users_only = false, want true")
+	}
+
+	// The fields of a single drive aren't wrapped in the list's fields
+	if fields := server.requests("/drive/v3/drives/drive-1")[0].Query.Get("fields"); fields != "id, name, restrictions/domainUsersOnly" {
+		t.Errorf("fields = %s, want id, name, restrictions/domainUsersOnly", fields)
+	}
+}
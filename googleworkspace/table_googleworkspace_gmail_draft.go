@@ -13,6 +13,12 @@ import (
 	"google.golang.org/api/gmail/v1"
 )
 
+type gmailDraft struct {
+	gmail.Draft
+	UserId string
+	FanOut *mailboxFanOut
+}
+
 //// TABLE DEFINITION
 
 func tableGoogleWorkspaceGmailDraft(_ context.Context) *plugin.Table {
@@ -24,7 +30,7 @@ func tableGoogleWorkspaceGmailDraft(_ context.Context) *plugin.Table {
 			KeyColumns: []*plugin.KeyColumn{
 				{
 					Name:    "user_id",
-					Require: plugin.Required,
+					Require: plugin.Optional,
 				},
 				{
 					Name:    "query",
@@ -57,9 +63,8 @@ func tableGoogleWorkspaceGmailDraft(_ context.Context) *plugin.Table {
 			},
 			{
 				Name:        "user_id",
-				Description: "User's email address. If not specified, indicates the current authenticated user.",
+				Description: "User's email address. If not specified, and enable_user_fan_out is set in the connection config, the mailboxes of every user in the domain are queried.",
 				Type:        proto.ColumnType_STRING,
-				Transform:   transform.FromQual("user_id"),
 			},
 			{
 				Name:        "message_history_id",
@@ -116,6 +121,12 @@ func tableGoogleWorkspaceGmailDraft(_ context.Context) *plugin.Table {
 				Hydrate:     getGmailDraft,
 				Transform:   transform.FromField("Message.Payload"),
 			},
+			{
+				Name:        "fan_out_error",
+				Description: "The error returned when querying the user's mailbox while fanning out across the domain. Null if the mailbox was queried successfully.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("FanOut").Transform(fanOutErrorMessage),
+			},
 		},
 	}
 }
@@ -123,17 +134,6 @@ func tableGoogleWorkspaceGmailDraft(_ context.Context) *plugin.Table {
 //// LIST FUNCTION
 
 func listGmailDrafts(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
-	// Create service
-	service, err := GmailService(ctx, d)
-	if err != nil {
-		return nil, err
-	}
-
-	var userID string
-	if d.KeyColumnQuals["user_id"] != nil {
-		userID = d.KeyColumnQuals["user_id"].GetStringValue()
-	}
-
 	var queryFilter, query string
 	var filter []string
 
@@ -177,10 +177,41 @@ func listGmailDrafts(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		}
 	}
 
+	// If the user is specified, list the drafts of that user only
+	if d.KeyColumnQuals["user_id"] != nil {
+		// Create service
+		service, err := GmailService(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		return nil, listGmailDraftsOfUser(ctx, d, service, d.KeyColumnQuals["user_id"].GetStringValue(), query, maxResults)
+	}
+
+	// Otherwise, list the drafts of every user in the domain, if enabled
+	if err := checkMailboxFanOut(d, "user_id"); err != nil {
+		return nil, err
+	}
+
+	err := forEachMailbox(ctx, d,
+		func(service *gmail.Service, userEmail string) error {
+			return listGmailDraftsOfUser(ctx, d, service, userEmail, query, maxResults)
+		},
+		func(userEmail string, err error) interface{} {
+			return gmailDraft{UserId: userEmail, FanOut: failedMailboxFanOut(err)}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func listGmailDraftsOfUser(ctx context.Context, d *plugin.QueryData, service *gmail.Service, userID string, query string, maxResults int64) error {
 	resp := service.Users.Drafts.List(userID).Q(query).MaxResults(maxResults)
-	if err := resp.Pages(ctx, func(page *gmail.ListDraftsResponse) error {
+	return resp.Pages(ctx, func(page *gmail.ListDraftsResponse) error {
 		for _, draft := range page.Drafts {
-			d.StreamListItem(ctx, draft)
+			d.StreamListItem(ctx, gmailDraft{Draft: *draft, UserId: userID, FanOut: newMailboxFanOut(d, "user_id")})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
 			if plugin.IsCancelled(ctx) {
@@ -189,31 +220,20 @@ func listGmailDrafts(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 			}
 		}
 		return nil
-	}); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
+	})
 }
 
 //// HYDRATE FUNCTIONS
 
 func getGmailDraft(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
-	// Create service
-	service, err := GmailService(ctx, d)
-	if err != nil {
-		return nil, err
-	}
-
-	var userID string
-	if d.KeyColumnQuals["user_id"] != nil {
-		userID = d.KeyColumnQuals["user_id"].GetStringValue()
-	}
-
-	var draftID string
+	var userID, draftID string
+	var fanOut *mailboxFanOut
 	if h.Item != nil {
-		draftID = h.Item.(*gmail.Draft).Id
+		userID = h.Item.(gmailDraft).UserId
+		draftID = h.Item.(gmailDraft).Id
+		fanOut = h.Item.(gmailDraft).FanOut
 	} else {
+		userID = d.KeyColumnQuals["user_id"].GetStringValue()
 		draftID = d.KeyColumnQuals["draft_id"].GetStringValue()
 	}
 
@@ -222,10 +242,16 @@ func getGmailDraft(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 		return nil, nil
 	}
 
+	// Create service
+	service, err := gmailServiceForMailbox(ctx, d, "user_id", userID)
+	if err != nil {
+		return nil, fanOut.hydrateError(err)
+	}
+
 	resp, err := service.Users.Drafts.Get(userID, draftID).Do()
 	if err != nil {
-		return nil, err
+		return nil, fanOut.hydrateError(err)
 	}
 
-	return resp, nil
+	return gmailDraft{Draft: *resp, UserId: userID}, nil
 }
@@ -14,6 +14,12 @@ import (
 	"google.golang.org/api/gmail/v1"
 )
 
+type gmailMessage struct {
+	gmail.Message
+	UserId string
+	FanOut *mailboxFanOut
+}
+
 //// TABLE DEFINITION
 
 func tableGoogleWorkspaceGmailMessage(_ context.Context) *plugin.Table {
@@ -25,7 +31,7 @@ func tableGoogleWorkspaceGmailMessage(_ context.Context) *plugin.Table {
 			KeyColumns: []*plugin.KeyColumn{
 				{
 					Name:    "user_id",
-					Require: plugin.Required,
+					Require: plugin.Optional,
 				},
 				{
 					Name:    "sender_email",
@@ -60,9 +66,8 @@ func tableGoogleWorkspaceGmailMessage(_ context.Context) *plugin.Table {
 			},
 			{
 				Name:        "user_id",
-				Description: "User's email address. If not specified, indicates the current authenticated user.",
+				Description: "User's email address. If not specified, and enable_user_fan_out is set in the connection config, the mailboxes of every user in the domain are queried.",
 				Type:        proto.ColumnType_STRING,
-				Transform:   transform.FromQual("user_id"),
 			},
 			{
 				Name:        "history_id",
@@ -120,6 +125,12 @@ func tableGoogleWorkspaceGmailMessage(_ context.Context) *plugin.Table {
 				Type:        proto.ColumnType_JSON,
 				Hydrate:     getGmailMessage,
 			},
+			{
+				Name:        "fan_out_error",
+				Description: "The error returned when querying the user's mailbox while fanning out across the domain. Null if the mailbox was queried successfully.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("FanOut").Transform(fanOutErrorMessage),
+			},
 		},
 	}
 }
@@ -127,17 +138,6 @@ func tableGoogleWorkspaceGmailMessage(_ context.Context) *plugin.Table {
 //// LIST FUNCTION
 
 func listGmailMessages(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
-	// Create service
-	service, err := GmailService(ctx, d)
-	if err != nil {
-		return nil, err
-	}
-
-	var userID string
-	if d.KeyColumnQuals["user_id"] != nil {
-		userID = d.KeyColumnQuals["user_id"].GetStringValue()
-	}
-
 	var queryFilter, query string
 	var filter []string
 
@@ -186,10 +186,41 @@ func listGmailMessages(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 		}
 	}
 
+	// If the user is specified, list the messages of that user only
+	if d.KeyColumnQuals["user_id"] != nil {
+		// Create service
+		service, err := GmailService(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		return nil, listGmailMessagesOfUser(ctx, d, service, d.KeyColumnQuals["user_id"].GetStringValue(), query, maxResults)
+	}
+
+	// Otherwise, list the messages of every user in the domain, if enabled
+	if err := checkMailboxFanOut(d, "user_id"); err != nil {
+		return nil, err
+	}
+
+	err := forEachMailbox(ctx, d,
+		func(service *gmail.Service, userEmail string) error {
+			return listGmailMessagesOfUser(ctx, d, service, userEmail, query, maxResults)
+		},
+		func(userEmail string, err error) interface{} {
+			return gmailMessage{UserId: userEmail, FanOut: failedMailboxFanOut(err)}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func listGmailMessagesOfUser(ctx context.Context, d *plugin.QueryData, service *gmail.Service, userID string, query string, maxResults int64) error {
 	resp := service.Users.Messages.List(userID).Q(query).MaxResults(maxResults)
-	if err := resp.Pages(ctx, func(page *gmail.ListMessagesResponse) error {
+	return resp.Pages(ctx, func(page *gmail.ListMessagesResponse) error {
 		for _, message := range page.Messages {
-			d.StreamListItem(ctx, message)
+			d.StreamListItem(ctx, gmailMessage{Message: *message, UserId: userID, FanOut: newMailboxFanOut(d, "user_id")})
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
 			if plugin.IsCancelled(ctx) {
@@ -198,31 +229,20 @@ func listGmailMessages(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 			}
 		}
 		return nil
-	}); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
+	})
 }
 
 //// HYDRATE FUNCTIONS
 
 func getGmailMessage(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
-	// Create service
-	service, err := GmailService(ctx, d)
-	if err != nil {
-		return nil, err
-	}
-
-	var userID string
-	if d.KeyColumnQuals["user_id"] != nil {
-		userID = d.KeyColumnQuals["user_id"].GetStringValue()
-	}
-
-	var messageID string
+	var userID, messageID string
+	var fanOut *mailboxFanOut
 	if h.Item != nil {
-		messageID = h.Item.(*gmail.Message).Id
+		userID = h.Item.(gmailMessage).UserId
+		messageID = h.Item.(gmailMessage).Id
+		fanOut = h.Item.(gmailMessage).FanOut
 	} else {
+		userID = d.KeyColumnQuals["user_id"].GetStringValue()
 		messageID = d.KeyColumnQuals["id"].GetStringValue()
 	}
 
@@ -231,23 +251,36 @@ func getGmailMessage(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrate
 		return nil, nil
 	}
 
+	// Create service
+	service, err := gmailServiceForMailbox(ctx, d, "user_id", userID)
+	if err != nil {
+		return nil, fanOut.hydrateError(err)
+	}
+
 	resp, err := service.Users.Messages.Get(userID, messageID).Do()
 	if err != nil {
-		return nil, err
+		return nil, fanOut.hydrateError(err)
 	}
 
-	return resp, nil
+	return gmailMessage{Message: *resp, UserId: userID}, nil
 }
 
 //// TRANSFORM FUNCTIONS
 
 func extractMessageSender(ctx context.Context, d *transform.TransformData) (interface{}, error) {
-	data := d.HydrateItem.(*gmail.Message)
-	if data.Payload == nil {
+	// The hydrate item is a gmailMessage when fetched for a given user, or a *gmail.Message for the authenticated user
+	var payload *gmail.MessagePart
+	switch item := d.HydrateItem.(type) {
+	case gmailMessage:
+		payload = item.Payload
+	case *gmail.Message:
+		payload = item.Payload
+	}
+	if payload == nil {
 		return nil, nil
 	}
 
-	for _, payloadHeader := range data.Payload.Headers {
+	for _, payloadHeader := range payload.Headers {
 		if payloadHeader.Name == "From" {
 			regexExp := regexp.MustCompile(`\<(.*?) *\>`)
 			senderEmail := regexExp.FindStringSubmatch(payloadHeader.Value)
@@ -0,0 +1,83 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListGmailMessagesOfGivenUser(t *testing.T) {
+	server := newReplayServer(t, "gmail")
+	table := tableGoogleWorkspaceGmailMessage(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{equalsQual("user_id", stringValue("john@example.com"))},
+	})
+	if len(items) != 1 {
+		t.Fatalf("listed %d messages, want 1", len(items))
+	}
+	if message := items[0].(gmailMessage); message.Id != "message-4" || message.UserId != "john@example.com" {
+		t.Errorf("listed %s of %s, want message-4 of john@example.com", message.Id, message.UserId)
+	}
+}
+
+func TestListGmailMessagesWithoutUserRequiresFanOut(t *testing.T) {
+	table := tableGoogleWorkspaceGmailMessage(context.Background())
+	d := testQuery{}.queryData(table, &plugin.Connection{Name: t.Name(), Config: googleworkspaceConfig{}})
+
+	if _, err := listGmailMessages(testContext(), d, &plugin.HydrateData{}); err == nil {
+		t.Error("listed the messages of every user without enable_user_fan_out")
+	}
+}
+
+func TestListGmailMessagesFanOutReturnsErrorRows(t *testing.T) {
+	server := newReplayServer(t, "gmail_fan_out")
+	table := tableGoogleWorkspaceGmailMessage(context.Background())
+	connection := server.delegatedConnection()
+
+	items := listRows(t, table, connection, testQuery{})
+	if len(items) != 3 {
+		t.Fatalf("listed %d messages, want 3", len(items))
+	}
+
+	// Hydrate the rows the way Steampipe does, before reading the fan_out_error column
+	d := testQuery{}.queryData(table, connection)
+	fanOutErrors := map[string]interface{}{}
+	for _, item := range items {
+		message := item.(gmailMessage)
+		if _, err := getGmailMessage(testContext(), d, &plugin.HydrateData{Item: item}); err != nil {
+			t.Fatalf("hydrating %s of %s: %v", message.Id, message.UserId, err)
+		}
+		fanOutErrors[message.UserId+"/"+message.Id] = columnValues(t, table, item, "fan_out_error")["fan_out_error"]
+	}
+
+	if fanOutErrors["jane@example.com/message-1"] != nil {
+		t.Errorf("message-1 has the fan-out error %v, want none", fanOutErrors["jane@example.com/message-1"])
+	}
+	if fanOutErrors["jane@example.com/message-2"] == nil {
+		t.Error("message-2, which couldn't be retrieved, has no fan-out error")
+	}
+	if fanOutErrors["john@example.com/"] == nil {
+		t.Error("john@example.com, whose mailbox couldn't be listed, has no fan-out error")
+	}
+}
+
+func TestListGmailMessagesFanOutRequiresDelegation(t *testing.T) {
+	server := newReplayServer(t, "gmail_fan_out")
+	table := tableGoogleWorkspaceGmailMessage(context.Background())
+
+	connection := server.connection()
+	config := connection.Config.(googleworkspaceConfig)
+	fanOut := true
+	config.EnableUserFanOut = &fanOut
+	connection.Config = config
+
+	if _, err := listGmailMessages(testContext(), testQuery{}.queryData(table, connection), &plugin.HydrateData{}); err == nil {
+		t.Error("listed the messages of every user with token_path credentials")
+	}
+	if len(server.requests("/admin/directory/v1/users")) != 0 {
+		t.Error("listed the users of the domain with token_path credentials")
+	}
+}
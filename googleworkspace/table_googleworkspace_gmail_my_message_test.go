@@ -0,0 +1,75 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestListGmailMyMessagesPushesDownQuals(t *testing.T) {
+	server := newReplayServer(t, "gmail")
+	table := tableGoogleWorkspaceGmailMyMessage(context.Background())
+
+	listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("sender_email", stringValue("jane@example.com")),
+			{Column: "internal_date", Operator: ">=", Value: timestampValue("2022-02-01T00:00:00Z")},
+			{Column: "internal_date", Operator: "<", Value: timestampValue("2022-03-01T00:00:00Z")},
+		},
+	})
+
+	wantQ := `from = "jane@example.com" and after:1643673600 and before:1646092800`
+	if q := server.requests("/gmail/v1/users/me/messages")[0].Query.Get("q"); q != wantQ {
+		t.Errorf("q = %s, want %s", q, wantQ)
+	}
+}
+
+func TestListGmailMyMessagesPaginatesUntilLimit(t *testing.T) {
+	server := newReplayServer(t, "gmail")
+	table := tableGoogleWorkspaceGmailMyMessage(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{})
+	if len(items) != 3 {
+		t.Errorf("listed %d messages, want 3", len(items))
+	}
+	if pages := len(server.requests("/gmail/v1/users/me/messages")); pages != 2 {
+		t.Errorf("listed %d pages, want 2", pages)
+	}
+
+	// With a limit, the next page must not be requested once the limit has been hit
+	server = newReplayServer(t, "gmail")
+	items = listRows(t, table, server.connection(), testQuery{limit: limit(2)})
+	if len(items) != 2 {
+		t.Errorf("listed %d messages, want 2", len(items))
+	}
+	requests := server.requests("/gmail/v1/users/me/messages")
+	if len(requests) != 1 {
+		t.Fatalf("listed %d pages, want 1", len(requests))
+	}
+	if maxResults := requests[0].Query.Get("maxResults"); maxResults != "2" {
+		t.Errorf("maxResults = %s, want the limit", maxResults)
+	}
+}
+
+func TestGetGmailMyMessageHydratesListedMessage(t *testing.T) {
+	server := newReplayServer(t, "gmail")
+	table := tableGoogleWorkspaceGmailMyMessage(context.Background())
+	d := testQuery{}.queryData(table, server.connection())
+
+	item, err := getGmailMyMessage(testContext(), d, &plugin.HydrateData{Item: &gmail.Message{Id: "message-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender, err := extractMessageSender(context.Background(), &transform.TransformData{HydrateItem: item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender != "jane@example.com" {
+		t.Errorf("sender_email = %v, want jane@example.com", sender)
+	}
+}
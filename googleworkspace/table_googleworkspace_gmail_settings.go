@@ -11,6 +11,11 @@ import (
 	"google.golang.org/api/googleapi"
 )
 
+type gmailSettingsUser struct {
+	gmail.Profile
+	FanOut *mailboxFanOut
+}
+
 //// TABLE DEFINITION
 
 func tableGoogleWorkspaceGmailSettings(_ context.Context) *plugin.Table {
@@ -19,12 +24,12 @@ func tableGoogleWorkspaceGmailSettings(_ context.Context) *plugin.Table {
 		Description: "Retrieves settings for the specified account.",
 		List: &plugin.ListConfig{
 			Hydrate:    listGmailUsers,
-			KeyColumns: plugin.SingleColumn("user_email"),
+			KeyColumns: plugin.OptionalColumns([]string{"user_email"}),
 		},
 		Columns: []*plugin.Column{
 			{
 				Name:        "user_email",
-				Description: "The specified user's email address.",
+				Description: "The specified user's email address. If not specified, and enable_user_fan_out is set in the connection config, the settings of every user in the domain are queried.",
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("EmailAddress"),
 			},
@@ -69,6 +74,12 @@ func tableGoogleWorkspaceGmailSettings(_ context.Context) *plugin.Table {
 				Hydrate:     getGmailVacationSetting,
 				Transform:   transform.FromValue(),
 			},
+			{
+				Name:        "fan_out_error",
+				Description: "The error returned when querying the user's settings while fanning out across the domain. Null if the settings were queried successfully.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("FanOut").Transform(fanOutErrorMessage),
+			},
 		},
 	}
 }
@@ -76,22 +87,44 @@ func tableGoogleWorkspaceGmailSettings(_ context.Context) *plugin.Table {
 //// LIST FUNCTION
 
 func listGmailUsers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
-	// Create service
-	service, err := GmailService(ctx, d)
-	if err != nil {
-		return nil, err
+	// If the user is specified, list the settings of that user only
+	if d.KeyColumnQuals["user_email"] != nil {
+		// Create service
+		service, err := GmailService(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := service.Users.GetProfile(d.KeyColumnQuals["user_email"].GetStringValue()).Do()
+		if err != nil {
+			return nil, err
+		}
+		d.StreamListItem(ctx, gmailSettingsUser{Profile: *resp})
+
+		return nil, nil
 	}
 
-	var userID string
-	if d.KeyColumnQuals["user_email"] != nil {
-		userID = d.KeyColumnQuals["user_email"].GetStringValue()
+	// Otherwise, list the settings of every user in the domain, if enabled
+	if err := checkMailboxFanOut(d, "user_email"); err != nil {
+		return nil, err
 	}
 
-	resp, err := service.Users.GetProfile(userID).Do()
+	err := forEachMailbox(ctx, d,
+		func(service *gmail.Service, userEmail string) error {
+			resp, err := service.Users.GetProfile(userEmail).Do()
+			if err != nil {
+				return err
+			}
+			d.StreamListItem(ctx, gmailSettingsUser{Profile: *resp, FanOut: newMailboxFanOut(d, "user_email")})
+			return nil
+		},
+		func(userEmail string, err error) interface{} {
+			return gmailSettingsUser{Profile: gmail.Profile{EmailAddress: userEmail}, FanOut: failedMailboxFanOut(err)}
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
-	d.StreamListItem(ctx, resp)
 
 	return nil, nil
 }
@@ -101,12 +134,19 @@ func listGmailUsers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 // Lists the delegates for the specified account.
 // Note: This method is only available to service account clients that have been delegated domain-wide authority.
 func listGmailDelegateSettings(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	user := h.Item.(gmailSettingsUser)
+
+	// Return nil, if the user's settings couldn't be listed while fanning out across the domain
+	if user.FanOut.failed() {
+		return nil, nil
+	}
+
 	// Create service
-	service, err := GmailService(ctx, d)
+	userID := user.EmailAddress
+	service, err := gmailServiceForMailbox(ctx, d, "user_email", userID)
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
-	userID := h.Item.(*gmail.Profile).EmailAddress
 
 	resp, err := service.Users.Settings.Delegates.List(userID).Do()
 	if err != nil {
@@ -117,7 +157,7 @@ func listGmailDelegateSettings(ctx context.Context, d *plugin.QueryData, h *plug
 				return nil, nil
 			}
 		}
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
 
 	return resp.Delegates, nil
@@ -125,16 +165,23 @@ func listGmailDelegateSettings(ctx context.Context, d *plugin.QueryData, h *plug
 
 // Gets the auto-forwarding setting for the specified account.
 func getGmailSettingAutoForwarding(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	user := h.Item.(gmailSettingsUser)
+
+	// Return nil, if the user's settings couldn't be listed while fanning out across the domain
+	if user.FanOut.failed() {
+		return nil, nil
+	}
+
 	// Create service
-	service, err := GmailService(ctx, d)
+	userID := user.EmailAddress
+	service, err := gmailServiceForMailbox(ctx, d, "user_email", userID)
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
-	userID := h.Item.(*gmail.Profile).EmailAddress
 
 	resp, err := service.Users.Settings.GetAutoForwarding(userID).Do()
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
 
 	// If the property is set with default value, it doesn't show in response
@@ -152,16 +199,23 @@ func getGmailSettingAutoForwarding(ctx context.Context, d *plugin.QueryData, h *
 
 // Gets IMAP settings.
 func getGmailSettingImap(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	user := h.Item.(gmailSettingsUser)
+
+	// Return nil, if the user's settings couldn't be listed while fanning out across the domain
+	if user.FanOut.failed() {
+		return nil, nil
+	}
+
 	// Create service
-	service, err := GmailService(ctx, d)
+	userID := user.EmailAddress
+	service, err := gmailServiceForMailbox(ctx, d, "user_email", userID)
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
-	userID := h.Item.(*gmail.Profile).EmailAddress
 
 	resp, err := service.Users.Settings.GetImap(userID).Do()
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
 
 	// If the property is set with default value, it doesn't show in response
@@ -180,16 +234,23 @@ func getGmailSettingImap(ctx context.Context, d *plugin.QueryData, h *plugin.Hyd
 
 // Gets language settings.
 func getGmailLanguage(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	user := h.Item.(gmailSettingsUser)
+
+	// Return nil, if the user's settings couldn't be listed while fanning out across the domain
+	if user.FanOut.failed() {
+		return nil, nil
+	}
+
 	// Create service
-	service, err := GmailService(ctx, d)
+	userID := user.EmailAddress
+	service, err := gmailServiceForMailbox(ctx, d, "user_email", userID)
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
-	userID := h.Item.(*gmail.Profile).EmailAddress
 
 	resp, err := service.Users.Settings.GetLanguage(userID).Do()
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
 
 	return resp, nil
@@ -197,16 +258,23 @@ func getGmailLanguage(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 
 // Gets POP settings.
 func getGmailPopSetting(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	user := h.Item.(gmailSettingsUser)
+
+	// Return nil, if the user's settings couldn't be listed while fanning out across the domain
+	if user.FanOut.failed() {
+		return nil, nil
+	}
+
 	// Create service
-	service, err := GmailService(ctx, d)
+	userID := user.EmailAddress
+	service, err := gmailServiceForMailbox(ctx, d, "user_email", userID)
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
-	userID := h.Item.(*gmail.Profile).EmailAddress
 
 	resp, err := service.Users.Settings.GetPop(userID).Do()
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
 
 	return resp, nil
@@ -214,16 +282,23 @@ func getGmailPopSetting(ctx context.Context, d *plugin.QueryData, h *plugin.Hydr
 
 // Gets vacation responder settings.
 func getGmailVacationSetting(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	user := h.Item.(gmailSettingsUser)
+
+	// Return nil, if the user's settings couldn't be listed while fanning out across the domain
+	if user.FanOut.failed() {
+		return nil, nil
+	}
+
 	// Create service
-	service, err := GmailService(ctx, d)
+	userID := user.EmailAddress
+	service, err := gmailServiceForMailbox(ctx, d, "user_email", userID)
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
-	userID := h.Item.(*gmail.Profile).EmailAddress
 
 	resp, err := service.Users.Settings.GetVacation(userID).Do()
 	if err != nil {
-		return nil, err
+		return nil, user.FanOut.hydrateError(err)
 	}
 
 	// If the property is set with default value, it doesn't show in response
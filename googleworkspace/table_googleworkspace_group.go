@@ -0,0 +1,190 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceGroup(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_group",
+		Description: "Groups in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listGroups,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "domain",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "user_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "query",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.AnyColumn([]string{"id", "email"}),
+			Hydrate:    getGroup,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The unique ID of the group.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "email",
+				Description: "The group's email address.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "name",
+				Description: "The group's display name.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "An extended description to help users determine the purpose of the group.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "domain",
+				Description: "The domain name of the group, derived from the group's email address.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Email").Transform(emailDomain),
+			},
+			{
+				Name:        "admin_created",
+				Description: "Indicates whether the group was created by an administrator rather than a user, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "direct_members_count",
+				Description: "The number of users that are direct members of the group. Members of child groups are not counted.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "user_key",
+				Description: "The email address or immutable ID of a user, used to list only the groups the user is a member of.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("user_key"),
+			},
+			{
+				Name:        "query",
+				Description: "A query string for [searching](https://developers.google.com/admin-sdk/directory/v1/guides/search-groups) group fields.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("query"),
+			},
+			{
+				Name:        "aliases",
+				Description: "A list of the group's alias email addresses.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "non_editable_aliases",
+				Description: "A list of the group's non-editable alias email addresses that are outside of the account's primary domain or subdomains.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listGroups(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 200 records in a single page
+	maxResult := int64(200)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	resp := service.Groups.List().MaxResults(maxResult)
+
+	// Either the customer, the domain or the user key must be specified
+	if d.KeyColumnQuals["domain"] != nil {
+		resp = resp.Domain(d.KeyColumnQuals["domain"].GetStringValue())
+	}
+	if d.KeyColumnQuals["user_key"] != nil {
+		resp = resp.UserKey(d.KeyColumnQuals["user_key"].GetStringValue())
+	}
+	if d.KeyColumnQuals["domain"] == nil && d.KeyColumnQuals["user_key"] == nil {
+		resp = resp.Customer("my_customer")
+	}
+
+	// Query string for searching group fields. Refer https://developers.google.com/admin-sdk/directory/v1/guides/search-groups
+	// For example, "email:admin*"
+	if d.KeyColumnQuals["query"] != nil {
+		resp = resp.Query(d.KeyColumnQuals["query"].GetStringValue())
+	}
+
+	if err := resp.Pages(ctx, func(page *admin.Groups) error {
+		for _, group := range page.Groups {
+			d.StreamListItem(ctx, group)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getGroup(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The group key can be the group's email address, group alias, or the unique group ID
+	groupKey := d.KeyColumnQuals["id"].GetStringValue()
+	if groupKey == "" {
+		groupKey = d.KeyColumnQuals["email"].GetStringValue()
+	}
+
+	// Return nil, if no input provided
+	if groupKey == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Groups.Get(groupKey).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,235 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// groupMember is a member of a group, directly or through a nested group
+type groupMember struct {
+	admin.Member
+	GroupId       string
+	GroupEmail    string
+	ViaGroupEmail string
+	Depth         int
+}
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceGroupMember(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_group_member",
+		Description: "Members of the groups in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listGroupMembers,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "group_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "group_email",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "role",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "expand_nested",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The unique ID of the group member.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "email",
+				Description: "The member's email address.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "group_id",
+				Description: "The unique ID of the group.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "group_email",
+				Description: "The group's email address.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "role",
+				Description: "The member's role in the group it directly belongs to, i.e. the group of via_group_email, which is a nested group for inherited members. Possible values are: OWNER, MANAGER and MEMBER.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "The type of group member. Possible values are: CUSTOMER, EXTERNAL, GROUP and USER.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "Status of member.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "delivery_settings",
+				Description: "Defines mail delivery preferences of member.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "via_group_email",
+				Description: "The email address of the group the member directly belongs to. Differs from group_email for members inherited through nested groups.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "depth",
+				Description: "The level of nesting at which the member was found; 1 for direct members of the group.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "expand_nested",
+				Description: "If set to true, members of nested groups are expanded recursively.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromQual("expand_nested"),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listGroupMembers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var expandNested bool
+	if d.KeyColumnQuals["expand_nested"] != nil {
+		expandNested = d.KeyColumnQuals["expand_nested"].GetBoolValue()
+	}
+
+	// Nested groups may contain members with any role, so the role filter
+	// can only be passed to the API when members are not expanded
+	var roles string
+	if d.KeyColumnQuals["role"] != nil && !expandNested {
+		roles = d.KeyColumnQuals["role"].GetStringValue()
+	}
+
+	// If the group is specified, list the members of that group only
+	var groupKey string
+	if d.KeyColumnQuals["group_id"] != nil {
+		groupKey = d.KeyColumnQuals["group_id"].GetStringValue()
+	} else if d.KeyColumnQuals["group_email"] != nil {
+		groupKey = d.KeyColumnQuals["group_email"].GetStringValue()
+	}
+
+	if groupKey != "" {
+		group, err := service.Groups.Get(groupKey).Do()
+		if err != nil {
+			if isNotFoundError([]string{"404"})(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return nil, listMembersOfGroup(ctx, d, service, group, group.Id, group.Email, 1, roles, expandNested, map[string]bool{})
+	}
+
+	// Otherwise, list the members of every group in the domain
+	resp := service.Groups.List().Customer("my_customer").MaxResults(200)
+	if err := resp.Pages(ctx, func(page *admin.Groups) error {
+		for _, group := range page.Groups {
+			if err := listMembersOfGroup(ctx, d, service, group, group.Id, group.Email, 1, roles, expandNested, map[string]bool{}); err != nil {
+				return err
+			}
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// listMembersOfGroup streams the members of the given group, and, if requested, walks
+// into every member of type GROUP. Groups already visited are skipped to avoid cycles.
+func listMembersOfGroup(ctx context.Context, d *plugin.QueryData, service *admin.Service, group *admin.Group, groupKey string, viaGroupEmail string, depth int, roles string, expandNested bool, visited map[string]bool) error {
+	if visited[groupKey] {
+		return nil
+	}
+	visited[groupKey] = true
+
+	// By default, API can return maximum 200 records in a single page
+	maxResult := int64(200)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	resp := service.Members.List(groupKey).MaxResults(maxResult)
+	if roles != "" {
+		resp = resp.Roles(roles)
+	}
+
+	var nestedGroups []*admin.Member
+	err := resp.Pages(ctx, func(page *admin.Members) error {
+		for _, member := range page.Members {
+			d.StreamListItem(ctx, groupMember{*member, group.Id, group.Email, viaGroupEmail, depth})
+
+			if expandNested && member.Type == "GROUP" {
+				nestedGroups = append(nestedGroups, member)
+			}
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// A nested group may belong to another customer, or may have been deleted
+		if depth > 1 && isNotFoundError([]string{"403", "404"})(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, nested := range nestedGroups {
+		if plugin.IsCancelled(ctx) {
+			return nil
+		}
+		if err := listMembersOfGroup(ctx, d, service, group, nested.Id, nested.Email, depth+1, roles, expandNested, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceLoginActivity(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_login_activity",
+		Description: "Login events of the users of the Google Workspace account, from the login activity report.",
+		List: &plugin.ListConfig{
+			Hydrate: listLoginActivities,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "user_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "actor_ip_address",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "customer_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:      "time",
+					Require:   plugin.Optional,
+					Operators: []string{">", ">=", "=", "<", "<="},
+				},
+				{
+					Name:    "event_name",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "time",
+				Description: "The time of the login event.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Id.Time"),
+			},
+			{
+				Name:        "event_name",
+				Description: "The name of the login event, e.g. login_success, login_failure, login_challenge or suspicious_login.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Name"),
+			},
+			{
+				Name:        "email",
+				Description: "The primary email address of the user who logged in.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.Email"),
+			},
+			{
+				Name:        "actor_ip_address",
+				Description: "The IP address the user logged in from.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("IpAddress"),
+			},
+			{
+				Name:        "login_type",
+				Description: "The type of credentials used to log in, e.g. google_password, saml or reauth.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.login_type"),
+			},
+			{
+				Name:        "login_challenge_method",
+				Description: "The methods the user was challenged with, e.g. password, idv_preregistered_phone or google_authenticator.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters.login_challenge_method"),
+			},
+			{
+				Name:        "login_challenge_status",
+				Description: "Whether the login challenge succeeded, e.g. Challenge Passed or Challenge Failed.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.login_challenge_status"),
+			},
+			{
+				Name:        "login_failure_type",
+				Description: "The reason the login failed, e.g. login_failure_invalid_password or login_failure_account_disabled.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.login_failure_type"),
+			},
+			{
+				Name:        "is_suspicious",
+				Description: "Indicates whether the login was flagged as suspicious by Google, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Parameters.is_suspicious"),
+			},
+			{
+				Name:        "is_second_factor",
+				Description: "Indicates whether a second factor was used to log in, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Parameters.is_second_factor"),
+			},
+			{
+				Name:        "affected_email_address",
+				Description: "The email address of the account affected by the event, e.g. the one disabled or attacked.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.affected_email_address"),
+			},
+			{
+				Name:        "event_type",
+				Description: "The type of the login event, e.g. login or account_warning.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Type"),
+			},
+			{
+				Name:        "parameters",
+				Description: "All the parameters of the login event by name, with plain JSON values.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters"),
+			},
+			{
+				Name:        "user_key",
+				Description: "The email address or profile ID of the user to list the login events of, as given in the where clause. Otherwise, the email address of the user.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor").Transform(activityUserKey),
+			},
+			{
+				Name:        "profile_id",
+				Description: "The unique Google Workspace profile ID of the user who logged in.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.ProfileId"),
+			},
+			{
+				Name:        "unique_qualifier",
+				Description: "Unique qualifier of the activity the event belongs to, if multiple activities have the same time.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.UniqueQualifier"),
+			},
+			{
+				Name:        "customer_id",
+				Description: "The unique identifier of the Google Workspace account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.CustomerId"),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listLoginActivities(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	call, err := newActivitiesListCall(ctx, d, "login")
+	if err != nil {
+		return nil, err
+	}
+
+	// The quals on the time column can't match any activity
+	if call == nil {
+		return nil, nil
+	}
+
+	if err := listActivityEvents(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+package googleworkspace
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListLoginActivitiesTypesLoginDetails(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceLoginActivity(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{equalsQual("event_name", stringValue("login_failure"))},
+	})
+	if len(items) != 1 {
+		t.Fatalf("listed %d login events, want 1", len(items))
+	}
+
+	got := columnValues(t, table, items[0], "email", "actor_ip_address", "login_type", "login_challenge_method", "login_failure_type", "is_suspicious", "is_second_factor", "affected_email_address")
+	want := map[string]interface{}{
+		"email":                  "john@example.com",
+		"actor_ip_address":       "203.0.113.11",
+		"login_type":             "google_password",
+		"login_challenge_method": []interface{}{"password"},
+		"login_failure_type":     "login_failure_invalid_password",
+		"is_suspicious":          true,
+		"is_second_factor":       false,
+		"affected_email_address": nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
+
+	request := server.requests("/admin/reports/v1/activity/users/all/applications/login")[0]
+	if eventName := request.Query.Get("eventName"); eventName != "login_failure" {
+		t.Errorf("eventName = %s, want login_failure", eventName)
+	}
+}
+
+func TestListLoginActivitiesOfUserByEmail(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceLoginActivity(context.Background())
+
+	userKey := equalsQual("user_key", stringValue("jane@example.com"))
+	items := listRows(t, table, server.connection(), testQuery{quals: []*quals.Qual{userKey}})
+	if len(items) != 2 {
+		t.Fatalf("listed %d login events, want 2", len(items))
+	}
+	if len(server.requests("/admin/reports/v1/activity/users/jane@example.com/applications/login")) != 1 {
+		t.Error("didn't list the login events of jane@example.com only")
+	}
+
+	// The rows must match the qual, which Steampipe checks again
+	for _, item := range items {
+		if value := qualColumnValue(t, table, item, "user_key", userKey); value != "jane@example.com" {
+			t.Errorf("user_key = %v, want jane@example.com", value)
+		}
+	}
+}
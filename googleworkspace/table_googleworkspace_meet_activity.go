@@ -0,0 +1,220 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+// The columns of the Meet activity table which are parameters of the events, and can be filtered on by the API
+var meetActivityFilterColumns = []string{"meeting_code", "conference_id", "organizer_email", "identifier", "device_type"}
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceMeetActivity(_ context.Context) *plugin.Table {
+	keyColumns := []*plugin.KeyColumn{
+		{
+			Name:      "time",
+			Require:   plugin.Optional,
+			Operators: []string{">", ">=", "=", "<", "<="},
+		},
+		{
+			Name:    "event_name",
+			Require: plugin.Optional,
+		},
+	}
+	for _, column := range meetActivityFilterColumns {
+		keyColumns = append(keyColumns, &plugin.KeyColumn{
+			Name:      column,
+			Require:   plugin.Optional,
+			Operators: []string{"=", "<>", "!="},
+		})
+	}
+
+	return &plugin.Table{
+		Name:        "googleworkspace_meet_activity",
+		Description: "Participations in Google Meet calls, with their call quality, from the Meet activity report.",
+		List: &plugin.ListConfig{
+			Hydrate:    listMeetActivities,
+			KeyColumns: keyColumns,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "time",
+				Description: "The time of the event, e.g. when the participant left the call.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Id.Time"),
+			},
+			{
+				Name:        "event_name",
+				Description: "The name of the event, e.g. call_ended when a participant leaves a call, or abuse_report_submitted.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Name"),
+			},
+			{
+				Name:        "meeting_code",
+				Description: "The code of the meeting, as in its URL.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.meeting_code"),
+			},
+			{
+				Name:        "conference_id",
+				Description: "The unique identifier of the conference, i.e. of one occurrence of the meeting.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.conference_id"),
+			},
+			{
+				Name:        "organizer_email",
+				Description: "The email address of the organizer of the meeting.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.organizer_email"),
+			},
+			{
+				Name:        "identifier",
+				Description: "The identifier of the participant, i.e. their email address or phone number.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.identifier"),
+			},
+			{
+				Name:        "identifier_type",
+				Description: "The type of the identifier of the participant, e.g. email_address or phone_number.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.identifier_type"),
+			},
+			{
+				Name:        "display_name",
+				Description: "The name of the participant, as displayed in the meeting.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.display_name"),
+			},
+			{
+				Name:        "is_external",
+				Description: "Indicates whether the participant is outside the Google Workspace account, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Parameters.is_external"),
+			},
+			{
+				Name:        "device_type",
+				Description: "The type of device the participant joined from, e.g. web, android, ios or meet_hardware.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.device_type"),
+			},
+			{
+				Name:        "duration_seconds",
+				Description: "The time the participant spent in the meeting, in seconds.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.duration_seconds"),
+			},
+			{
+				Name:        "network_rtt_msec_mean",
+				Description: "The mean network round trip time of the participant, in milliseconds.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.network_rtt_msec_mean"),
+			},
+			{
+				Name:        "network_recv_jitter_msec_mean",
+				Description: "The mean jitter of the packets received by the participant, in milliseconds.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.network_recv_jitter_msec_mean"),
+			},
+			{
+				Name:        "network_recv_jitter_msec_max",
+				Description: "The maximum jitter of the packets received by the participant, in milliseconds.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.network_recv_jitter_msec_max"),
+			},
+			{
+				Name:        "network_send_jitter_msec_mean",
+				Description: "The mean jitter of the packets sent by the participant, in milliseconds.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.network_send_jitter_msec_mean"),
+			},
+			{
+				Name:        "network_congestion",
+				Description: "The percentage of the time the network of the participant was congested.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.network_congestion"),
+			},
+			{
+				Name:        "audio_recv_packet_loss_mean",
+				Description: "The mean percentage of the audio packets received by the participant which were lost.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.audio_recv_packet_loss_mean"),
+			},
+			{
+				Name:        "audio_send_packet_loss_mean",
+				Description: "The mean percentage of the audio packets sent by the participant which were lost.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.audio_send_packet_loss_mean"),
+			},
+			{
+				Name:        "video_recv_packet_loss_mean",
+				Description: "The mean percentage of the video packets received by the participant which were lost.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.video_recv_packet_loss_mean"),
+			},
+			{
+				Name:        "video_send_packet_loss_mean",
+				Description: "The mean percentage of the video packets sent by the participant which were lost.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Parameters.video_send_packet_loss_mean"),
+			},
+			{
+				Name:        "location_country",
+				Description: "The country the participant joined from.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.location_country"),
+			},
+			{
+				Name:        "ip_address",
+				Description: "The IP address the participant joined from.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Parameters.ip_address"),
+			},
+			{
+				Name:        "parameters",
+				Description: "All the parameters of the event by name, with plain JSON values.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Parameters"),
+			},
+			{
+				Name:        "email",
+				Description: "The primary email address of the participant, if they are a user of the Google Workspace account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Actor.Email"),
+			},
+			{
+				Name:        "unique_qualifier",
+				Description: "Unique qualifier of the activity the event belongs to, if multiple activities have the same time.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Id.UniqueQualifier"),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listMeetActivities(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	call, err := newActivitiesListCall(ctx, d, "meet")
+	if err != nil {
+		return nil, err
+	}
+
+	// The quals on the time column can't match any activity
+	if call == nil {
+		return nil, nil
+	}
+
+	if filters := activityParameterFilters(d, meetActivityFilterColumns); filters != "" {
+		call.Filters(filters)
+	}
+
+	if err := listActivityEvents(ctx, d, call); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
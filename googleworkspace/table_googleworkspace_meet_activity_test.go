@@ -0,0 +1,49 @@
+package googleworkspace
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListMeetActivitiesTypesCallQuality(t *testing.T) {
+	server := newReplayServer(t, "admin_reports")
+	table := tableGoogleWorkspaceMeetActivity(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("meeting_code", stringValue("ABCDEFGHIJ")),
+			equalsQual("event_name", stringValue("call_ended")),
+		},
+	})
+	if len(items) != 2 {
+		t.Fatalf("listed %d participations, want 2", len(items))
+	}
+
+	columns := []string{"conference_id", "organizer_email", "identifier", "is_external", "device_type", "duration_seconds", "network_rtt_msec_mean", "network_recv_jitter_msec_mean", "audio_recv_packet_loss_mean", "email"}
+	want := map[string]interface{}{
+		"conference_id":                 "conf-1",
+		"organizer_email":               "jane@example.com",
+		"identifier":                    "guest@example.org",
+		"is_external":                   true,
+		"device_type":                   "android",
+		"duration_seconds":              int64(1200),
+		"network_rtt_msec_mean":         int64(350),
+		"network_recv_jitter_msec_mean": int64(60),
+		"audio_recv_packet_loss_mean":   int64(8),
+		"email":                         "",
+	}
+	if got := columnValues(t, table, items[1], columns...); !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
+
+	request := server.requests("/admin/reports/v1/activity/users/all/applications/meet")[0]
+	if filters := request.Query.Get("filters"); filters != "meeting_code==ABCDEFGHIJ" {
+		t.Errorf("filters = %s, want meeting_code==ABCDEFGHIJ", filters)
+	}
+	if eventName := request.Query.Get("eventName"); eventName != "call_ended" {
+		t.Errorf("eventName = %s, want call_ended", eventName)
+	}
+}
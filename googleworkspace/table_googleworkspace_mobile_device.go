@@ -0,0 +1,316 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceMobileDevice(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_mobile_device",
+		Description: "Mobile devices managed in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listMobileDevices,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "query",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "projection",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("resource_id"),
+			Hydrate:    getMobileDevice,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "resource_id",
+				Description: "The unique ID the API service uses to identify the mobile device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "device_id",
+				Description: "The serial number for a Google Sync mobile device. For Android and iOS devices, this is a software generated unique identifier.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "model",
+				Description: "The mobile device's model name.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "The type of mobile device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "The device's status.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "os",
+				Description: "The mobile device's operating system, for example IOS 4.3 or Android 2.3.5.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "release_version",
+				Description: "The mobile device's release version.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "build_number",
+				Description: "The mobile device's operating system build number.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "kernel_version",
+				Description: "The mobile device's kernel version.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "security_patch_level",
+				Description: "The mobile device's security patch level.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("SecurityPatchLevel").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "device_compromised_status",
+				Description: "The compromised device status. Possible values are: \"Undetected\", \"Compromised\" and \"No compromise detected\".",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "device_password_status",
+				Description: "The mobile device's password status.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "encryption_status",
+				Description: "The mobile device's encryption status.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "privilege",
+				Description: "The DMAgentPermission of the device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "first_sync",
+				Description: "The date and time the device was initially synchronized with the policy settings in the Admin console.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "last_sync",
+				Description: "The date and time the device was last synchronized with the policy settings in the Admin console.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "adb_status",
+				Description: "Indicates whether Android Debug Bridge is enabled on the device, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("AdbStatus"),
+			},
+			{
+				Name:        "developer_options_status",
+				Description: "Indicates whether developer options are enabled on the device, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("DeveloperOptionsStatus"),
+			},
+			{
+				Name:        "unknown_sources_status",
+				Description: "Indicates whether the device allows installing apps from unknown sources, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("UnknownSourcesStatus"),
+			},
+			{
+				Name:        "supports_work_profile",
+				Description: "Indicates whether the device supports a work profile, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("SupportsWorkProfile"),
+			},
+			{
+				Name:        "managed_account_is_on_owner_profile",
+				Description: "Indicates whether the managed account is on the device owner's profile, or on a work profile.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("ManagedAccountIsOnOwnerProfile"),
+			},
+			{
+				Name:        "manufacturer",
+				Description: "The mobile device's manufacturer.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "brand",
+				Description: "The mobile device's brand.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "hardware",
+				Description: "The mobile device's hardware.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "hardware_id",
+				Description: "The IMEI/MEID unique identifier for Android hardware.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "serial_number",
+				Description: "The mobile device's serial number.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "imei",
+				Description: "The mobile device's IMEI number.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "meid",
+				Description: "The mobile device's MEID number.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "wifi_mac_address",
+				Description: "The mobile device's MAC address on Wi-Fi networks.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "network_operator",
+				Description: "The mobile device's mobile or network operator.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "default_language",
+				Description: "The default locale used on the device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "user_agent",
+				Description: "The user agent of the mobile device.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "query",
+				Description: "A query string for [searching](https://developers.google.com/admin-sdk/directory/v1/search-operators) mobile device fields.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("query"),
+			},
+			{
+				Name:        "projection",
+				Description: "Restricts the information returned to a set of selected fields. Possible values are: BASIC and FULL. Defaults to FULL.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("projection"),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "email",
+				Description: "A list of the owner's email addresses.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "name",
+				Description: "A list of the owner's user names.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "applications",
+				Description: "A list of applications installed on an Android mobile device.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "other_accounts_info",
+				Description: "A list of accounts added on the device.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listMobileDevices(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 100 records in a single page
+	maxResult := int64(100)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	// The owner's email and the compromised status are only returned in the FULL projection
+	projection := "FULL"
+	if d.KeyColumnQuals["projection"] != nil {
+		projection = d.KeyColumnQuals["projection"].GetStringValue()
+	}
+
+	resp := service.Mobiledevices.List("my_customer").Projection(projection).MaxResults(maxResult)
+
+	// Query string for searching mobile device fields. Refer https://developers.google.com/admin-sdk/directory/v1/search-operators
+	// For example, "status:approved"
+	if d.KeyColumnQuals["query"] != nil {
+		resp = resp.Query(d.KeyColumnQuals["query"].GetStringValue())
+	}
+
+	if err := resp.Pages(ctx, func(page *admin.MobileDevices) error {
+		for _, device := range page.Mobiledevices {
+			d.StreamListItem(ctx, device)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getMobileDevice(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	resourceID := d.KeyColumnQuals["resource_id"].GetStringValue()
+
+	// Return nil, if no input provided
+	if resourceID == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Mobiledevices.Get("my_customer", resourceID).Projection("FULL").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,196 @@
+package googleworkspace
+
+import (
+	"context"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// orgUnit is an organizational unit, with the number of its direct child units
+type orgUnit struct {
+	admin.OrgUnit
+	ChildCount int
+}
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceOrgUnit(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_org_unit",
+		Description: "Organizational units in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listOrgUnits,
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.AnyColumn([]string{"org_unit_id", "org_unit_path"}),
+			Hydrate:    getOrgUnit,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The organizational unit's path name.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "org_unit_id",
+				Description: "The unique ID of the organizational unit.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "org_unit_path",
+				Description: "The full path to the organizational unit.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "reports_org_unit_id",
+				Description: "The unique ID of the organizational unit without the \"id:\" prefix, as accepted by the org_unit_id qualifier of the Admin Reports tables.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OrgUnitId").Transform(trimOrgUnitIdPrefix),
+			},
+			{
+				Name:        "parent_org_unit_id",
+				Description: "The unique ID of the parent organizational unit.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "parent_org_unit_path",
+				Description: "The organizational unit's parent path.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "Description of the organizational unit.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "block_inheritance",
+				Description: "Indicates whether the organizational unit blocks inheritance of settings from its parent, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "depth",
+				Description: "The number of levels between the root organizational unit and this unit; 1 for top-level units.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("OrgUnitPath").Transform(orgUnitPathDepth),
+			},
+			{
+				Name:        "child_count",
+				Description: "The number of organizational units directly under this unit.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("ChildCount"),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "ancestor_paths",
+				Description: "The paths of all the organizational units above this unit, starting from the root unit.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("OrgUnitPath").Transform(orgUnitAncestorPaths),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listOrgUnits(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API doesn't paginate, and returns every organizational unit in a single response
+	resp, err := service.Orgunits.List("my_customer").Type("all").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	// Count the children of every unit, since the full tree is available
+	childCounts := map[string]int{}
+	for _, unit := range resp.OrganizationUnits {
+		childCounts[unit.ParentOrgUnitPath]++
+	}
+
+	for _, unit := range resp.OrganizationUnits {
+		d.StreamListItem(ctx, orgUnit{*unit, childCounts[unit.OrgUnitPath]})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if plugin.IsCancelled(ctx) {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getOrgUnit(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API accepts either the full path of the unit, or its unique ID
+	orgUnitKey := d.KeyColumnQuals["org_unit_id"].GetStringValue()
+	if orgUnitKey == "" {
+		orgUnitKey = strings.TrimPrefix(d.KeyColumnQuals["org_unit_path"].GetStringValue(), "/")
+	}
+
+	// Return nil, if no input provided
+	if orgUnitKey == "" {
+		return nil, nil
+	}
+
+	unit, err := service.Orgunits.Get("my_customer", orgUnitKey).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := service.Orgunits.List("my_customer").OrgUnitPath(unit.OrgUnitPath).Type("children").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return orgUnit{*unit, len(children.OrganizationUnits)}, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func orgUnitPathDepth(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	path := strings.Trim(d.Value.(string), "/")
+	if path == "" {
+		return 0, nil
+	}
+	return len(strings.Split(path, "/")), nil
+}
+
+func trimOrgUnitIdPrefix(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	return strings.TrimPrefix(d.Value.(string), "id:"), nil
+}
+
+func orgUnitAncestorPaths(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	path := strings.Trim(d.Value.(string), "/")
+	if path == "" {
+		return nil, nil
+	}
+
+	// For example, "/Sales/EMEA/France" has the ancestors "/", "/Sales" and "/Sales/EMEA"
+	ancestors := []string{"/"}
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		ancestors = append(ancestors, "/"+strings.Join(segments[:i], "/"))
+	}
+
+	return ancestors, nil
+}
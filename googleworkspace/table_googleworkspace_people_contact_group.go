@@ -112,12 +112,20 @@ func listPeopleContactGroups(ctx context.Context, d *plugin.QueryData, _ *plugin
 	}
 
 	var contactGroupNames [][]string
+	var contactGroupCount int64
 	resp := service.ContactGroups.List().PageSize(pageLimit)
 	if err := resp.Pages(ctx, func(page *people.ListContactGroupsResponse) error {
 		var resourceNames []string
 		// create a chunk of resourceNames of size 200
 		for _, contactGroup := range page.ContactGroups {
 			resourceNames = append(resourceNames, contactGroup.ResourceName)
+			contactGroupCount++
+
+			// The contact groups are only streamed once listed, so stop listing once the limit is reached
+			if limit != nil && contactGroupCount >= *limit {
+				page.NextPageToken = ""
+				break
+			}
 
 			// Context can be cancelled due to manual cancellation or the limit has been hit
 			if plugin.IsCancelled(ctx) {
@@ -142,6 +150,11 @@ func listPeopleContactGroups(ctx context.Context, d *plugin.QueryData, _ *plugin
 		if data.Responses != nil && len(data.Responses) > 0 {
 			for _, i := range data.Responses {
 				d.StreamListItem(ctx, i.ContactGroup)
+
+				// Context can be cancelled due to manual cancellation or the limit has been hit
+				if plugin.IsCancelled(ctx) {
+					return nil, nil
+				}
 			}
 		}
 	}
@@ -0,0 +1,62 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+	"google.golang.org/api/people/v1"
+)
+
+func TestListPeopleContactGroupsPaginatesAndBatchesGets(t *testing.T) {
+	server := newReplayServer(t, "people")
+	table := tableGoogleWorkspacePeopleContactGroup(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{equalsQual("max_members", &proto.QualValue{Value: &proto.QualValue_Int64Value{Int64Value: 10}})},
+	})
+
+	var names []string
+	for _, item := range items {
+		names = append(names, item.(*people.ContactGroup).Name)
+	}
+	if len(names) != 3 || names[0] != "Family" || names[2] != "Work" {
+		t.Errorf("listed contact groups %v, want Family, Friends and Work", names)
+	}
+
+	if pages := len(server.requests("/v1/contactGroups")); pages != 2 {
+		t.Errorf("listed %d pages, want 2", pages)
+	}
+
+	// Every page of contact groups is fetched with a single batch get
+	batches := server.requests("/v1/contactGroups:batchGet")
+	if len(batches) != 2 {
+		t.Fatalf("got %d batches, want 2", len(batches))
+	}
+	if resourceNames := batches[0].Query["resourceNames"]; len(resourceNames) != 2 {
+		t.Errorf("first batch got %v, want the 2 contact groups of the first page", resourceNames)
+	}
+	if maxMembers := batches[0].Query.Get("maxMembers"); maxMembers != "10" {
+		t.Errorf("maxMembers = %s, want 10", maxMembers)
+	}
+}
+
+func TestListPeopleContactGroupsStopsAtLimit(t *testing.T) {
+	server := newReplayServer(t, "people")
+	table := tableGoogleWorkspacePeopleContactGroup(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{limit: limit(1)})
+	if len(items) != 1 {
+		t.Errorf("listed %d contact groups, want 1", len(items))
+	}
+
+	// The contact groups are streamed after listing, so listing must stop by itself once the limit is reached
+	if pages := len(server.requests("/v1/contactGroups")); pages != 1 {
+		t.Errorf("listed %d pages, want 1", pages)
+	}
+	batches := server.requests("/v1/contactGroups:batchGet")
+	if len(batches) != 1 || len(batches[0].Query["resourceNames"]) != 1 {
+		t.Errorf("got batches %v, want a single batch of 1 contact group", batches)
+	}
+}
@@ -0,0 +1,35 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+)
+
+func TestListPeopleContactsFlattensSingletonFields(t *testing.T) {
+	server := newReplayServer(t, "people")
+	table := tableGoogleWorkspacePeopleContact(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{})
+	if len(items) != 1 {
+		t.Fatalf("listed %d contacts, want 1", len(items))
+	}
+
+	contact := items[0].(contacts)
+	if contact.Name.DisplayName != "Jane Doe" {
+		t.Errorf("display name = %s, want Jane Doe", contact.Name.DisplayName)
+	}
+
+	email, err := extractPrimaryEmailAddress(context.Background(), &transform.TransformData{HydrateItem: contact})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if email != "jane@example.com" {
+		t.Errorf("primary email = %v, want jane@example.com", email)
+	}
+
+	if personFields := server.requests("/v1/people/me/connections")[0].Query.Get("personFields"); personFields == "" {
+		t.Error("no personFields were requested")
+	}
+}
@@ -0,0 +1,98 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// privilege is a privilege, with the name of the privilege it is a child of
+type privilege struct {
+	admin.Privilege
+	ParentPrivilegeName string
+}
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspacePrivilege(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_privilege",
+		Description: "Privileges that can be granted to administrator roles in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listPrivileges,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "privilege_name",
+				Description: "The name of the privilege.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "service_id",
+				Description: "The obfuscated ID of the service this privilege is for.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "service_name",
+				Description: "The name of the service this privilege is for.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "is_ou_scopable",
+				Description: "Indicates whether the privilege can be restricted to an organizational unit, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("IsOuScopable"),
+			},
+			{
+				Name:        "parent_privilege_name",
+				Description: "The name of the privilege this privilege is nested under. Null for top-level privileges.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listPrivileges(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API doesn't paginate, and returns every privilege in a single response
+	resp, err := service.Privileges.List("my_customer").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	// Privileges are returned as a tree; stream one row for every node
+	var streamPrivileges func(items []*admin.Privilege, parentName string) bool
+	streamPrivileges = func(items []*admin.Privilege, parentName string) bool {
+		for _, item := range items {
+			d.StreamListItem(ctx, privilege{*item, parentName})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				return false
+			}
+			if !streamPrivileges(item.ChildPrivileges, item.PrivilegeName) {
+				return false
+			}
+		}
+		return true
+	}
+	streamPrivileges(resp.Items, "")
+
+	return nil, nil
+}
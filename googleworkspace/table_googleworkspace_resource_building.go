@@ -0,0 +1,132 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceResourceBuilding(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_resource_building",
+		Description: "Buildings of the Google Workspace customer, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listResourceBuildings,
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("building_id"),
+			Hydrate:    getResourceBuilding,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "building_id",
+				Description: "The unique ID for the building.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "building_name",
+				Description: "The building name as seen by users in Calendar.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "A brief description of the building.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "latitude",
+				Description: "Latitude in decimal degrees.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("Coordinates.Latitude"),
+			},
+			{
+				Name:        "longitude",
+				Description: "Longitude in decimal degrees.",
+				Type:        proto.ColumnType_DOUBLE,
+				Transform:   transform.FromField("Coordinates.Longitude"),
+			},
+			{
+				Name:        "etags",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "address",
+				Description: "The postal address of the building.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "floor_names",
+				Description: "The display names for all floors in this building, ordered from lowest to highest.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listResourceBuildings(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 500 records in a single page
+	maxResult := int64(500)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	resp := service.Resources.Buildings.List("my_customer").MaxResults(maxResult)
+	if err := resp.Pages(ctx, func(page *admin.Buildings) error {
+		for _, building := range page.Buildings {
+			d.StreamListItem(ctx, building)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getResourceBuilding(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	buildingID := d.KeyColumnQuals["building_id"].GetStringValue()
+
+	// Return nil, if no input provided
+	if buildingID == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Resources.Buildings.Get("my_customer", buildingID).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,199 @@
+package googleworkspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceResourceCalendar(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_resource_calendar",
+		Description: "Calendar resources, such as meeting rooms, of the Google Workspace customer, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listResourceCalendars,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "building_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "resource_category",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "query",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("resource_id"),
+			Hydrate:    getResourceCalendar,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "resource_id",
+				Description: "The unique ID for the calendar resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_name",
+				Description: "The name of the calendar resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_email",
+				Description: "The read-only email for the calendar resource. Can be used as the calendar_id of the googleworkspace_calendar_event table.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "generated_resource_name",
+				Description: "The read-only auto-generated name of the calendar resource which includes metadata about the resource such as building name, floor, capacity, etc.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_category",
+				Description: "The category of the calendar resource. Possible values are: CONFERENCE_ROOM, OTHER and CATEGORY_UNKNOWN.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_type",
+				Description: "The type of the calendar resource, intended for non-room resources.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_description",
+				Description: "Description of the resource, visible only to admins.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "user_visible_description",
+				Description: "Description of the resource, visible to users and admins.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "building_id",
+				Description: "The unique ID for the building a resource is located in.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "floor_name",
+				Description: "The name of the floor a resource is located on.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "floor_section",
+				Description: "The name of the section within the floor a resource is located in.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "capacity",
+				Description: "The capacity of a resource, number of seats in a room.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "query",
+				Description: "A query string for [searching](https://developers.google.com/admin-sdk/directory/reference/rest/v1/resources.calendars/list#query-parameters) calendar resource fields.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("query"),
+			},
+			{
+				Name:        "etags",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "feature_instances",
+				Description: "A list of the features of the resource.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listResourceCalendars(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 500 records in a single page
+	maxResult := int64(500)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	// Query string for searching calendar resource fields.
+	// For example, "resourceCategory=CONFERENCE_ROOM AND capacity>=10"
+	var filter []string
+	if d.KeyColumnQuals["query"] != nil {
+		filter = append(filter, d.KeyColumnQuals["query"].GetStringValue())
+	}
+	if d.KeyColumnQuals["building_id"] != nil {
+		filter = append(filter, fmt.Sprintf("buildingId=\"%s\"", d.KeyColumnQuals["building_id"].GetStringValue()))
+	}
+	if d.KeyColumnQuals["resource_category"] != nil {
+		filter = append(filter, fmt.Sprintf("resourceCategory=%s", d.KeyColumnQuals["resource_category"].GetStringValue()))
+	}
+
+	resp := service.Resources.Calendars.List("my_customer").MaxResults(maxResult)
+	if len(filter) > 0 {
+		resp = resp.Query(strings.Join(filter, " AND "))
+	}
+
+	if err := resp.Pages(ctx, func(page *admin.CalendarResources) error {
+		for _, resource := range page.Items {
+			d.StreamListItem(ctx, resource)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getResourceCalendar(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	resourceID := d.KeyColumnQuals["resource_id"].GetStringValue()
+
+	// Return nil, if no input provided
+	if resourceID == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Resources.Calendars.Get("my_customer", resourceID).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
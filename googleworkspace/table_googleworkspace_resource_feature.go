@@ -0,0 +1,99 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceResourceFeature(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_resource_feature",
+		Description: "Features of the calendar resources, such as video conferencing equipment, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listResourceFeatures,
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("name"),
+			Hydrate:    getResourceFeature,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The name of the feature.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "etags",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listResourceFeatures(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 500 records in a single page
+	maxResult := int64(500)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	resp := service.Resources.Features.List("my_customer").MaxResults(maxResult)
+	if err := resp.Pages(ctx, func(page *admin.Features) error {
+		for _, feature := range page.Features {
+			d.StreamListItem(ctx, feature)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getResourceFeature(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	name := d.KeyColumnQuals["name"].GetStringValue()
+
+	// Return nil, if no input provided
+	if name == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Resources.Features.Get("my_customer", name).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,128 @@
+package googleworkspace
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceRole(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_role",
+		Description: "Administrator roles in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listRoles,
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("role_id"),
+			Hydrate:    getRole,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "role_id",
+				Description: "The unique ID of the role.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "role_name",
+				Description: "The name of the role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "role_description",
+				Description: "A short description of the role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "is_super_admin_role",
+				Description: "Indicates whether the role is a super admin role, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("IsSuperAdminRole"),
+			},
+			{
+				Name:        "is_system_role",
+				Description: "Indicates whether the role is a pre-defined system role, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("IsSystemRole"),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "role_privileges",
+				Description: "The set of privileges that are granted to this role.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listRoles(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 100 records in a single page
+	maxResult := int64(100)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	resp := service.Roles.List("my_customer").MaxResults(maxResult)
+	if err := resp.Pages(ctx, func(page *admin.Roles) error {
+		for _, role := range page.Items {
+			d.StreamListItem(ctx, role)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getRole(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	roleID := d.KeyColumnQuals["role_id"].GetInt64Value()
+
+	// Return nil, if no input provided
+	if roleID == 0 {
+		return nil, nil
+	}
+
+	resp, err := service.Roles.Get("my_customer", strconv.FormatInt(roleID, 10)).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
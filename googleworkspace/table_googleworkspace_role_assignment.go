@@ -0,0 +1,271 @@
+package googleworkspace
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// roleAssignment is a role assignment, with the assignees looked up by the query it was listed by.
+// The assignment is embedded by value, since the transforms of the columns don't follow embedded pointers.
+type roleAssignment struct {
+	admin.RoleAssignment
+	Assignees *roleAssignees
+}
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceRoleAssignment(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_role_assignment",
+		Description: "Assignments of administrator roles in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listRoleAssignments,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "role_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "assigned_to",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "user_key",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.SingleColumn("role_assignment_id"),
+			Hydrate:    getRoleAssignment,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "role_assignment_id",
+				Description: "The unique ID of the role assignment.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "role_id",
+				Description: "The ID of the role that is assigned.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "assigned_to",
+				Description: "The unique ID of the user or group this role is assigned to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "assignee_email",
+				Description: "The email address of the user or group this role is assigned to.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getRoleAssignmentAssignee,
+				Transform:   transform.FromField("Email"),
+			},
+			{
+				Name:        "assignee_type",
+				Description: "The type of the assignee. Possible values are: USER and GROUP.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getRoleAssignmentAssignee,
+				Transform:   transform.FromField("Type"),
+			},
+			{
+				Name:        "scope_type",
+				Description: "The scope in which this role is assigned. Possible values are: CUSTOMER and ORG_UNIT.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "org_unit_id",
+				Description: "If the role is restricted to an organizational unit, the ID of that unit without the \"id:\" prefix.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "user_key",
+				Description: "The primary email address, alias email address, or unique ID of the user or group to list the role assignments of.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("user_key"),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listRoleAssignments(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d, admin.AdminDirectoryRolemanagementReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 200 records in a single page
+	maxResult := int64(200)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	resp := service.RoleAssignments.List("my_customer").MaxResults(maxResult)
+
+	// The same users and groups are usually assigned several roles, so each of them is only looked up once per query
+	assignees := &roleAssignees{}
+
+	if d.KeyColumnQuals["role_id"] != nil {
+		resp = resp.RoleId(strconv.FormatInt(d.KeyColumnQuals["role_id"].GetInt64Value(), 10))
+	}
+
+	// The API also accepts an email address as the user key, but assigned_to only ever holds an ID,
+	// so an email address given for assigned_to can't match any row
+	if d.KeyColumnQuals["user_key"] != nil {
+		resp = resp.UserKey(d.KeyColumnQuals["user_key"].GetStringValue())
+	} else if d.KeyColumnQuals["assigned_to"] != nil {
+		assignedTo := d.KeyColumnQuals["assigned_to"].GetStringValue()
+		if strings.Contains(assignedTo, "@") {
+			return nil, nil
+		}
+		resp = resp.UserKey(assignedTo)
+	}
+
+	if err := resp.Pages(ctx, func(page *admin.RoleAssignments) error {
+		for _, assignment := range page.Items {
+			d.StreamListItem(ctx, roleAssignment{*assignment, assignees})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getRoleAssignment(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d, admin.AdminDirectoryRolemanagementReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+	assignmentID := d.KeyColumnQuals["role_assignment_id"].GetInt64Value()
+
+	// Return nil, if no input provided
+	if assignmentID == 0 {
+		return nil, nil
+	}
+
+	resp, err := service.RoleAssignments.Get("my_customer", strconv.FormatInt(assignmentID, 10)).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return roleAssignment{*resp, &roleAssignees{}}, nil
+}
+
+// Resolves the email address of the user, or the group, the role is assigned to.
+func getRoleAssignmentAssignee(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	assignment := h.Item.(roleAssignment)
+
+	// Return nil, if no input provided
+	if assignment.AssignedTo == "" {
+		return nil, nil
+	}
+
+	assignee, err := assignment.Assignees.get(ctx, d, assignment.AssignedTo)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignee == nil {
+		return nil, nil
+	}
+	return assignee, nil
+}
+
+// roleAssignee is the user or group a role is assigned to
+type roleAssignee struct {
+	Email string
+	Type  string
+}
+
+// roleAssignees holds the assignees looked up by a query, by ID. The rows of the query are hydrated
+// concurrently, so an assignee being looked up for a row is waited for by the other rows.
+type roleAssignees struct {
+	mu      sync.Mutex
+	lookups map[string]*roleAssigneeLookup
+}
+
+// roleAssigneeLookup is the lookup of an assignee, which is done once
+type roleAssigneeLookup struct {
+	once     sync.Once
+	assignee *roleAssignee
+	err      error
+}
+
+// Returns the user or group with the given ID, or nil if there is none, looking it up unless the query already did
+func (a *roleAssignees) get(ctx context.Context, d *plugin.QueryData, assignedTo string) (*roleAssignee, error) {
+	a.mu.Lock()
+	if a.lookups == nil {
+		a.lookups = map[string]*roleAssigneeLookup{}
+	}
+	lookup, ok := a.lookups[assignedTo]
+	if !ok {
+		lookup = &roleAssigneeLookup{}
+		a.lookups[assignedTo] = lookup
+	}
+	a.mu.Unlock()
+
+	lookup.once.Do(func() {
+		lookup.assignee, lookup.err = lookupRoleAssignee(ctx, d, assignedTo)
+	})
+	return lookup.assignee, lookup.err
+}
+
+// Returns the user or group with the given ID, or nil if there is none
+func lookupRoleAssignee(ctx context.Context, d *plugin.QueryData, assignedTo string) (*roleAssignee, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d, admin.AdminDirectoryUserReadonlyScope, admin.AdminDirectoryGroupReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := service.Users.Get(assignedTo).Fields("primaryEmail").Context(ctx).Do()
+	if err == nil {
+		return &roleAssignee{Email: user.PrimaryEmail, Type: "USER"}, nil
+	}
+	if !isNotFoundError([]string{"404"})(err) {
+		return nil, err
+	}
+
+	// Roles can also be assigned to security groups
+	group, err := service.Groups.Get(assignedTo).Fields("email").Context(ctx).Do()
+	if err == nil {
+		return &roleAssignee{Email: group.Email, Type: "GROUP"}, nil
+	}
+	if !isNotFoundError([]string{"404"})(err) {
+		return nil, err
+	}
+
+	return nil, nil
+}
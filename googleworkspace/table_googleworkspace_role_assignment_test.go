@@ -0,0 +1,67 @@
+package googleworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/quals"
+)
+
+func TestListRoleAssignmentsByUserEmail(t *testing.T) {
+	server := newReplayServer(t, "directory")
+	table := tableGoogleWorkspaceRoleAssignment(context.Background())
+	connection := server.connection()
+
+	userKey := equalsQual("user_key", stringValue("jane@example.com"))
+	query := testQuery{quals: []*quals.Qual{userKey}}
+	items := listRows(t, table, connection, query)
+	if len(items) != 2 {
+		t.Fatalf("listed %d role assignments, want 2", len(items))
+	}
+	if userKey := qualColumnValue(t, table, items[0], "user_key", userKey); userKey != "jane@example.com" {
+		t.Errorf("user_key = %v, want jane@example.com", userKey)
+	}
+
+	// Both roles are assigned to the same user, who is only looked up once
+	d := query.queryData(table, connection)
+	for _, item := range items {
+		assignee, err := getRoleAssignmentAssignee(testContext(), d, &plugin.HydrateData{Item: item})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if email := assignee.(*roleAssignee).Email; email != "jane@example.com" {
+			t.Errorf("assignee of %d = %s, want jane@example.com", item.(roleAssignment).RoleAssignmentId, email)
+		}
+	}
+	if lookups := len(server.requests("/admin/directory/v1/users/110000000000000000001")); lookups != 1 {
+		t.Errorf("looked up the assignee %d times, want once", lookups)
+	}
+
+	// Another query looks the assignee up again, in case it was renamed since
+	item := listRows(t, table, connection, query)[0]
+	if _, err := getRoleAssignmentAssignee(testContext(), d, &plugin.HydrateData{Item: item}); err != nil {
+		t.Fatal(err)
+	}
+	if lookups := len(server.requests("/admin/directory/v1/users/110000000000000000001")); lookups != 2 {
+		t.Errorf("looked up the assignee %d times over two queries, want twice", lookups)
+	}
+}
+
+func TestListRoleAssignmentsSkipsEmailAssignedTo(t *testing.T) {
+	server := newReplayServer(t, "directory")
+	table := tableGoogleWorkspaceRoleAssignment(context.Background())
+
+	// assigned_to holds IDs, so an email address can't match any row
+	items := listRows(t, table, server.connection(), testQuery{
+		quals: []*quals.Qual{
+			equalsQual("assigned_to", stringValue("jane@example.com")),
+		},
+	})
+	if len(items) != 0 {
+		t.Errorf("listed %d role assignments, want none", len(items))
+	}
+	if len(server.requests("/admin/directory/v1/customer/my_customer/roleassignments")) != 0 {
+		t.Error("requested role assignments for an email address given for assigned_to")
+	}
+}
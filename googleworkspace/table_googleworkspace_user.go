@@ -0,0 +1,528 @@
+package googleworkspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceUser(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_user",
+		Description: "Users in the Google Workspace domain, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listUsers,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "query",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "org_unit_path",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "domain",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "show_deleted",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.AnyColumn([]string{"id", "primary_email"}),
+			Hydrate:    getUser,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The unique ID for the user.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "primary_email",
+				Description: "The user's primary email address.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "full_name",
+				Description: "The user's full name formed by concatenating the first and last name values.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name.FullName"),
+			},
+			{
+				Name:        "given_name",
+				Description: "The user's first name.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name.GivenName"),
+			},
+			{
+				Name:        "family_name",
+				Description: "The user's last name.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name.FamilyName"),
+			},
+			{
+				Name:        "org_unit_path",
+				Description: "The full path of the parent organization associated with the user.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "customer_id",
+				Description: "The customer ID to retrieve all account users.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "domain",
+				Description: "The domain name of the user, derived from the primary email address.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrimaryEmail").Transform(emailDomain),
+			},
+			{
+				Name:        "suspended",
+				Description: "Indicates whether the user is suspended, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "suspension_reason",
+				Description: "The reason a user account is suspended either by the administrator or by Google at the time of suspension.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "archived",
+				Description: "Indicates whether the user is archived, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_admin",
+				Description: "Indicates whether the user is a super administrator, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_delegated_admin",
+				Description: "Indicates whether the user is a delegated administrator, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_enrolled_in_2sv",
+				Description: "Indicates whether the user is enrolled in 2-step verification, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("IsEnrolledIn2Sv"),
+			},
+			{
+				Name:        "is_enforced_in_2sv",
+				Description: "Indicates whether 2-step verification is enforced for the user, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("IsEnforcedIn2Sv"),
+			},
+			{
+				Name:        "is_mailbox_setup",
+				Description: "Indicates whether the user's Google mailbox is created, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "agreed_to_terms",
+				Description: "Indicates whether the user has completed an initial login and accepted the Terms of Service agreement, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "change_password_at_next_login",
+				Description: "Indicates whether the user is forced to change their password at next login, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "include_in_global_address_list",
+				Description: "Indicates whether the user's profile is visible in the Google Workspace global address list, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "ip_whitelisted",
+				Description: "Indicates whether the user's IP address is subject to a deprecated IP address allowlist configuration, or not.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "creation_time",
+				Description: "The time the user's account was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "last_login_time",
+				Description: "The last time the user logged into the user's account. Null if the user has never logged in.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("LastLoginTime").Transform(nullIfEpochTime),
+			},
+			{
+				Name:        "deletion_time",
+				Description: "The time the user's account was deleted.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "recovery_email",
+				Description: "Recovery email of the user.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "recovery_phone",
+				Description: "Recovery phone of the user.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "thumbnail_photo_url",
+				Description: "Photo URL of the user.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "query",
+				Description: "A query string for [searching](https://developers.google.com/admin-sdk/directory/v1/guides/search-users) user fields.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("query"),
+			},
+			{
+				Name:        "show_deleted",
+				Description: "If set to true, retrieves the list of users deleted in the last 20 days.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromQual("show_deleted"),
+			},
+			{
+				Name:        "aliases",
+				Description: "A list of the user's alias email addresses.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "non_editable_aliases",
+				Description: "A list of the user's non-editable alias email addresses. These are typically outside the account's primary domain or sub-domain.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "emails",
+				Description: "A list of the user's email addresses.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "external_ids",
+				Description: "A list of external IDs for the user, such as an employee or network ID.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "organizations",
+				Description: "A list of organizations the user belongs to.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "phones",
+				Description: "A list of the user's phone numbers.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "relations",
+				Description: "A list of the user's relationships to other users.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "addresses",
+				Description: "A list of the user's addresses.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "custom_schemas",
+				Description: "The values of the user's custom schema fields, keyed by schema name. Only retrieved when the column is selected.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listUsers(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d, admin.AdminDirectoryUserReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, API can return maximum 500 records in a single page
+	maxResult := int64(500)
+
+	limit := d.QueryContext.Limit
+	if d.QueryContext.Limit != nil {
+		if *limit < maxResult {
+			maxResult = *limit
+		}
+	}
+
+	resp := service.Users.List().MaxResults(maxResult)
+
+	// Either the customer or the domain must be specified
+	if d.KeyColumnQuals["domain"] != nil {
+		resp = resp.Domain(d.KeyColumnQuals["domain"].GetStringValue())
+	} else {
+		resp = resp.Customer("my_customer")
+	}
+
+	// Query string for searching user fields. Refer https://developers.google.com/admin-sdk/directory/v1/guides/search-users
+	// For example, "isSuspended=true"
+	var filter []string
+	if d.KeyColumnQuals["query"] != nil {
+		filter = append(filter, d.KeyColumnQuals["query"].GetStringValue())
+	}
+
+	// Note: the API matches users in the given organizational unit, and all of its sub units
+	if d.KeyColumnQuals["org_unit_path"] != nil {
+		filter = append(filter, fmt.Sprintf("orgUnitPath='%s'", d.KeyColumnQuals["org_unit_path"].GetStringValue()))
+	}
+
+	if len(filter) > 0 {
+		resp = resp.Query(strings.Join(filter, " "))
+	}
+
+	if d.KeyColumnQuals["show_deleted"] != nil && d.KeyColumnQuals["show_deleted"].GetBoolValue() {
+		resp = resp.ShowDeleted("true")
+	}
+
+	// Custom schema fields are only returned if explicitly requested
+	projection, customFieldMask, err := userProjection(d)
+	if err != nil {
+		return nil, err
+	}
+	if projection != "" {
+		resp = resp.Projection(projection)
+	}
+	if customFieldMask != "" {
+		resp = resp.CustomFieldMask(customFieldMask)
+	}
+
+	if err := resp.Pages(ctx, func(page *admin.Users) error {
+		for _, user := range page.Users {
+			d.StreamListItem(ctx, user)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if plugin.IsCancelled(ctx) {
+				page.NextPageToken = ""
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getUser(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d, admin.AdminDirectoryUserReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user key can be the user's primary email address, alias email address, or unique user ID
+	userKey := d.KeyColumnQuals["id"].GetStringValue()
+	if userKey == "" {
+		userKey = d.KeyColumnQuals["primary_email"].GetStringValue()
+	}
+
+	// Return nil, if no input provided
+	if userKey == "" {
+		return nil, nil
+	}
+
+	call := service.Users.Get(userKey)
+
+	// Custom schema fields are only returned if explicitly requested
+	projection, customFieldMask, err := userProjection(d)
+	if err != nil {
+		return nil, err
+	}
+	if projection != "" {
+		call = call.Projection(projection)
+	}
+	if customFieldMask != "" {
+		call = call.CustomFieldMask(customFieldMask)
+	}
+
+	resp, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// userProjection returns the projection and the comma-separated names of the custom schemas
+// to retrieve, based on the columns requested in the query. Selecting custom_schemas retrieves
+// every schema using the full projection, without listing the schemas of the customer, while
+// the typed custom columns retrieve their own schema only.
+func userProjection(d *plugin.QueryData) (string, string, error) {
+	fields, err := userCustomSchemaFields(GetConfig(d.Connection))
+	if err != nil {
+		return "", "", err
+	}
+
+	var schemaNames []string
+	seen := map[string]bool{}
+	for _, column := range d.QueryContext.Columns {
+		if column == "custom_schemas" {
+			return "full", "", nil
+		}
+		for _, field := range fields {
+			if field.ColumnName == column && !seen[field.SchemaName] {
+				seen[field.SchemaName] = true
+				schemaNames = append(schemaNames, field.SchemaName)
+			}
+		}
+	}
+
+	if len(schemaNames) == 0 {
+		return "", "", nil
+	}
+	return "custom", strings.Join(schemaNames, ","), nil
+}
+
+//// CUSTOM SCHEMA COLUMNS
+
+// The column types custom schema fields can be exposed as, using the
+// user_custom_schema_fields connection config argument
+var userCustomSchemaColumnTypes = map[string]proto.ColumnType{
+	"BOOL":      proto.ColumnType_BOOL,
+	"DOUBLE":    proto.ColumnType_DOUBLE,
+	"INT":       proto.ColumnType_INT,
+	"JSON":      proto.ColumnType_JSON,
+	"STRING":    proto.ColumnType_STRING,
+	"TIMESTAMP": proto.ColumnType_TIMESTAMP,
+}
+
+type userCustomSchemaField struct {
+	SchemaName string
+	FieldName  string
+	ColumnName string
+	ColumnType proto.ColumnType
+}
+
+// userCustomSchemaFields parses the user_custom_schema_fields connection config argument.
+// Each entry has the form "SchemaName.FieldName", optionally followed by ":TYPE".
+// For example, "EmployeeInfo.CostCenter" or "EmployeeInfo.Level:INT".
+func userCustomSchemaFields(config googleworkspaceConfig) ([]userCustomSchemaField, error) {
+	var fields []userCustomSchemaField
+	for _, entry := range config.UserCustomSchemaFields {
+		path, columnTypeName := entry, "STRING"
+		if i := strings.LastIndex(entry, ":"); i >= 0 {
+			path, columnTypeName = entry[:i], strings.ToUpper(entry[i+1:])
+		}
+
+		parts := strings.Split(path, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid user_custom_schema_fields entry %q: expected the form SchemaName.FieldName[:TYPE]", entry)
+		}
+
+		columnType, ok := userCustomSchemaColumnTypes[columnTypeName]
+		if !ok {
+			return nil, fmt.Errorf("invalid user_custom_schema_fields entry %q: unsupported type %q", entry, columnTypeName)
+		}
+
+		fields = append(fields, userCustomSchemaField{
+			SchemaName: parts[0],
+			FieldName:  parts[1],
+			ColumnName: "custom_" + strcase.ToSnake(parts[0]) + "_" + strcase.ToSnake(parts[1]),
+			ColumnType: columnType,
+		})
+	}
+
+	return fields, nil
+}
+
+// userCustomSchemaColumns returns a typed column for each custom schema field
+// listed in the user_custom_schema_fields connection config argument
+func userCustomSchemaColumns(ctx context.Context, config googleworkspaceConfig) ([]*plugin.Column, error) {
+	fields, err := userCustomSchemaFields(config)
+	if err != nil {
+		return nil, err
+	}
+
+	columnNames := map[string]bool{}
+	for _, column := range tableGoogleWorkspaceUser(ctx).Columns {
+		columnNames[column.Name] = true
+	}
+
+	var columns []*plugin.Column
+	for _, field := range fields {
+		if columnNames[field.ColumnName] {
+			return nil, fmt.Errorf("invalid user_custom_schema_fields entry %s.%s: column %q is already defined", field.SchemaName, field.FieldName, field.ColumnName)
+		}
+		columnNames[field.ColumnName] = true
+
+		columns = append(columns, &plugin.Column{
+			Name:        field.ColumnName,
+			Description: fmt.Sprintf("The value of the %s field of the %s custom schema.", field.FieldName, field.SchemaName),
+			Type:        field.ColumnType,
+			Transform:   transform.FromField("CustomSchemas").TransformP(userCustomSchemaFieldValue, field),
+		})
+	}
+
+	return columns, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func userCustomSchemaFieldValue(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	field := d.Param.(userCustomSchemaField)
+
+	customSchemas, ok := d.Value.(map[string]googleapi.RawMessage)
+	if !ok || customSchemas[field.SchemaName] == nil {
+		return nil, nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(customSchemas[field.SchemaName], &values); err != nil {
+		return nil, err
+	}
+
+	// Multi-valued fields are returned as a list of objects, e.g. [{"type": "work", "value": "1"}],
+	// and should be declared as JSON
+	return values[field.FieldName], nil
+}
+
+// The API returns the Unix epoch as the last login time of users who have never logged in
+func nullIfEpochTime(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	if d.Value == nil {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, d.Value.(string))
+	if err != nil || t.Unix() == 0 {
+		return nil, nil
+	}
+
+	return d.Value, nil
+}
+
+func emailDomain(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	if d.Value == nil {
+		return nil, nil
+	}
+
+	email := d.Value.(string)
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		return email[i+1:], nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,106 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+)
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceUserSchema(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_user_schema",
+		Description: "Custom user schemas of the Google Workspace customer, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listUserSchemas,
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: plugin.AnyColumn([]string{"schema_id", "schema_name"}),
+			Hydrate:    getUserSchema,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "schema_id",
+				Description: "The unique identifier of the schema.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "schema_name",
+				Description: "The schema's name, used as the key of the user's custom_schemas.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "display_name",
+				Description: "Display name for the schema.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "fields",
+				Description: "A list of fields in the schema, with their names, types, and whether they are multi-valued.",
+				Type:        proto.ColumnType_JSON,
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listUserSchemas(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API doesn't paginate, and returns every schema in a single response
+	resp, err := service.Schemas.List("my_customer").Do()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, schema := range resp.Schemas {
+		d.StreamListItem(ctx, schema)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if plugin.IsCancelled(ctx) {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getUserSchema(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// The schema key can be the schema's name, or its unique ID
+	schemaKey := d.KeyColumnQuals["schema_id"].GetStringValue()
+	if schemaKey == "" {
+		schemaKey = d.KeyColumnQuals["schema_name"].GetStringValue()
+	}
+
+	// Return nil, if no input provided
+	if schemaKey == "" {
+		return nil, nil
+	}
+
+	resp, err := service.Schemas.Get("my_customer", schemaKey).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
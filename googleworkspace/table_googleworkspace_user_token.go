@@ -0,0 +1,141 @@
+package googleworkspace
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// userToken is a token issued to an application, with the key of the user it was listed for.
+// While listing the tokens of every user, a user whose tokens couldn't be listed gets a row of
+// its own, with the error instead of a token.
+type userToken struct {
+	admin.Token
+	QueriedUserKey string
+	FanOutError    string
+}
+
+//// TABLE DEFINITION
+
+func tableGoogleWorkspaceUserToken(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "googleworkspace_user_token",
+		Description: "Third-party applications a user has granted access to, retrieved using the Admin SDK Directory API.",
+		List: &plugin.ListConfig{
+			Hydrate: listUserTokens,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "user_key",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "user_key",
+				Description: "The user's primary email address, or the user key specified in the where clause.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("QueriedUserKey"),
+			},
+			{
+				Name:        "user_id",
+				Description: "The unique ID of the user that issued the token.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("UserKey"),
+			},
+			{
+				Name:        "client_id",
+				Description: "The client ID of the application the token is issued to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "display_text",
+				Description: "The displayable name of the application the token is issued to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "anonymous",
+				Description: "Indicates whether the application is registered with Google, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Anonymous"),
+			},
+			{
+				Name:        "native_app",
+				Description: "Indicates whether the token is issued to an installed application, or not.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("NativeApp"),
+			},
+			{
+				Name:        "etag",
+				Description: "ETag of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "scopes",
+				Description: "A list of authorization scopes the application is granted.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "fan_out_error",
+				Description: "The error listing the tokens of the user, if user_key wasn't specified in the where clause and they couldn't be listed; the row then has no token.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("FanOutError").NullIfZero(),
+			},
+		},
+	}
+}
+
+//// LIST FUNCTION
+
+func listUserTokens(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create service
+	service, err := DirectoryService(ctx, d, admin.AdminDirectoryUserSecurityScope)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the user is specified, list the tokens of that user only
+	if d.KeyColumnQuals["user_key"] != nil {
+		return nil, listTokensOfUser(ctx, d, service, d.KeyColumnQuals["user_key"].GetStringValue())
+	}
+
+	// Otherwise, list the tokens of every user in the domain, a bounded number of users at a time.
+	// An error listing the tokens of a user doesn't fail the query, but is streamed as a row.
+	err = forEachDomainUser(ctx, d, "", userFanOutConcurrency(d), func(user *admin.User) error {
+		if err := listTokensOfUser(ctx, d, service, user.PrimaryEmail); err != nil {
+			d.StreamListItem(ctx, userToken{QueriedUserKey: user.PrimaryEmail, FanOutError: err.Error()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func listTokensOfUser(ctx context.Context, d *plugin.QueryData, service *admin.Service, userKey string) error {
+	resp, err := service.Tokens.List(userKey).Context(ctx).Do()
+	if err != nil {
+		// The user may have been deleted since the users were listed
+		if isNotFoundError([]string{"404"})(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, token := range resp.Items {
+		d.StreamListItem(ctx, userToken{Token: *token, QueriedUserKey: userKey})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if plugin.IsCancelled(ctx) {
+			break
+		}
+	}
+
+	return nil
+}
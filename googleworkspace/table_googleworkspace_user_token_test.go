@@ -0,0 +1,39 @@
+package googleworkspace
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestListUserTokensReturnsErrorRows(t *testing.T) {
+	server := newReplayServer(t, "user_token")
+	table := tableGoogleWorkspaceUserToken(context.Background())
+
+	items := listRows(t, table, server.connection(), testQuery{})
+	if len(items) != 2 {
+		t.Fatalf("listed %d tokens, want 2", len(items))
+	}
+
+	// The users are queried in parallel, so the rows are in no particular order
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].(userToken).QueriedUserKey < items[j].(userToken).QueriedUserKey
+	})
+
+	token := items[0].(userToken)
+	if token.ClientId != "client-1.apps.googleusercontent.com" {
+		t.Errorf("listed the token of %s, want client-1.apps.googleusercontent.com", token.ClientId)
+	}
+	want := map[string]interface{}{"user_key": "jane@example.com", "fan_out_error": nil}
+	if got := columnValues(t, table, token, "user_key", "fan_out_error"); !reflect.DeepEqual(got, want) {
+		t.Errorf("token = %v, want %v", got, want)
+	}
+
+	// The user whose tokens couldn't be listed is returned with the error, instead of failing the query
+	failed := items[1].(userToken)
+	got := columnValues(t, table, failed, "user_key", "fan_out_error")
+	if got["user_key"] != "john@example.com" || got["fan_out_error"] == nil || failed.ClientId != "" {
+		t.Errorf("row of john@example.com = %v, want the error listing the tokens", got)
+	}
+}
@@ -0,0 +1,152 @@
+package googleworkspace
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// tokenFile is the content of the token_path file written by the login subcommand. It uses the
+// same format as the authorized_user files written by gcloud, plus the last access token, so
+// that it can be reused across plugin restarts.
+type tokenFile struct {
+	Type         string    `json:"type"`
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret"`
+	RefreshToken string    `json:"refresh_token"`
+	AccessToken  string    `json:"access_token,omitempty"`
+	TokenExpiry  time.Time `json:"token_expiry,omitempty"`
+}
+
+// Returns every scope used by the plugin's tables, which is what the login subcommand requests
+func pluginScopes() []string {
+	var scopes []string
+	for _, s := range apiScopes {
+		scopes = append(scopes, s...)
+	}
+	for _, s := range optInScopes {
+		scopes = append(scopes, s...)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func readTokenFile(path string) (*tokenFile, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file tokenFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// writeTokenFile replaces the token file atomically, so that a concurrent reader, or a crash
+// while writing, never sees a truncated file
+func writeTokenFile(path string, file *tokenFile) error {
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// tokenFileSource refreshes the access token of an authorized_user token file, and writes
+// refreshed tokens back to the file
+type tokenFileSource struct {
+	base oauth2.TokenSource
+	path string
+
+	mu   sync.Mutex
+	file tokenFile
+}
+
+func newTokenFileSource(ctx context.Context, path string, file *tokenFile, endpoint oauth2.Endpoint) oauth2.TokenSource {
+	config := &oauth2.Config{
+		ClientID:     file.ClientID,
+		ClientSecret: file.ClientSecret,
+		Endpoint:     endpoint,
+	}
+	token := &oauth2.Token{
+		AccessToken:  file.AccessToken,
+		RefreshToken: file.RefreshToken,
+		Expiry:       file.TokenExpiry,
+	}
+
+	return &tokenFileSource{
+		base: config.TokenSource(ctx, token),
+		path: path,
+		file: *file,
+	}
+}
+
+func (ts *tokenFileSource) Token() (*oauth2.Token, error) {
+	token, err := ts.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if token.AccessToken == ts.file.AccessToken {
+		return token, nil
+	}
+
+	ts.file.AccessToken = token.AccessToken
+	ts.file.TokenExpiry = token.Expiry
+	if token.RefreshToken != "" {
+		ts.file.RefreshToken = token.RefreshToken
+	}
+
+	// Failing to persist the token doesn't prevent using it; the token is refreshed again on restart
+	_ = writeTokenFile(ts.path, &ts.file)
+
+	return token, nil
+}
+
+// Returns the token source of the given token file, if it was written by the login subcommand.
+// Other credential files, such as the authorized_user files written by gcloud, which have no
+// access token, are left to the client library and never written to.
+func tokenFileTokenSource(ctx context.Context, path string) (oauth2.TokenSource, bool) {
+	file, err := readTokenFile(path)
+	if err != nil || file.Type != "authorized_user" || file.RefreshToken == "" || file.AccessToken == "" {
+		return nil, false
+	}
+	return newTokenFileSource(ctx, path, file, google.Endpoint), true
+}
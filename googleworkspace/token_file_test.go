@@ -0,0 +1,85 @@
+package googleworkspace
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestTokenFileSourceWritesRefreshedToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.FormValue("refresh_token"); got != "refresh-token" {
+			t.Errorf("refresh_token = %q, want %q", got, "refresh-token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"refreshed-token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "token.json")
+	file := &tokenFile{
+		Type:         "authorized_user",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RefreshToken: "refresh-token",
+		AccessToken:  "expired-token",
+		TokenExpiry:  time.Now().Add(-time.Hour),
+	}
+	if err := writeTokenFile(path, file); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, ok := tokenFileTokenSource(context.Background(), path)
+	if !ok {
+		t.Fatal("tokenFileTokenSource() didn't accept the token file")
+	}
+	ts.(*tokenFileSource).base = (&oauth2.Config{
+		ClientID:     file.ClientID,
+		ClientSecret: file.ClientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: server.URL},
+	}).TokenSource(context.Background(), &oauth2.Token{RefreshToken: file.RefreshToken, Expiry: file.TokenExpiry})
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "refreshed-token" {
+		t.Errorf("AccessToken = %q, want %q", token.AccessToken, "refreshed-token")
+	}
+
+	written, err := readTokenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written.AccessToken != "refreshed-token" || written.RefreshToken != "refresh-token" || written.ClientID != "client-id" {
+		t.Errorf("token file = %+v, want the refreshed access token and the original client and refresh token", written)
+	}
+
+	// The file is replaced by renaming a temporary file, which must not be left behind
+	entries, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("the token file directory has %d entries, want 1", len(entries))
+	}
+}
+
+func TestTokenFileTokenSourceIgnoresGcloudFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "application_default_credentials.json")
+	content := `{"type":"authorized_user","client_id":"client-id","client_secret":"client-secret","refresh_token":"refresh-token"}`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tokenFileTokenSource(context.Background(), path); ok {
+		t.Error("tokenFileTokenSource() accepted a token file written by gcloud")
+	}
+}
@@ -0,0 +1,229 @@
+package googleworkspace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v3/plugin/transform"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/gmail/v1"
+)
+
+// The default number of users queried in parallel when fanning out across the domain
+const defaultUserFanOutConcurrency = 10
+
+// Returns true if per-user tables may query every user in the domain when no user is specified
+func userFanOutEnabled(d *plugin.QueryData) bool {
+	config := GetConfig(d.Connection)
+	return config.EnableUserFanOut != nil && *config.EnableUserFanOut
+}
+
+// Returns the number of users queried in parallel when fanning out across the domain
+func userFanOutConcurrency(d *plugin.QueryData) int {
+	config := GetConfig(d.Connection)
+	if config.UserFanOutConcurrency != nil && *config.UserFanOutConcurrency > 0 {
+		return *config.UserFanOutConcurrency
+	}
+	return defaultUserFanOutConcurrency
+}
+
+// forEachDomainUser calls fn for every user in the domain matching the given query, with
+// at most maxConcurrency calls running in parallel. The first error returned by fn stops
+// the enumeration, and is returned once the calls in progress have completed.
+func forEachDomainUser(ctx context.Context, d *plugin.QueryData, query string, maxConcurrency int, fn func(user *admin.User) error) error {
+	// Create service
+	service, err := DirectoryService(ctx, d, admin.AdminDirectoryUserReadonlyScope)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var fnErr error
+	sem := make(chan struct{}, maxConcurrency)
+
+	resp := service.Users.List().Customer("my_customer").Fields("nextPageToken", "users(id,primaryEmail,isMailboxSetup)").MaxResults(500)
+	if query != "" {
+		resp = resp.Query(query)
+	}
+	err = resp.Pages(ctx, func(page *admin.Users) error {
+		for _, user := range page.Users {
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			mu.Lock()
+			failed := fnErr != nil
+			mu.Unlock()
+			if plugin.IsCancelled(ctx) || failed {
+				page.NextPageToken = ""
+				break
+			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(user *admin.User) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := fn(user); err != nil {
+					mu.Lock()
+					if fnErr == nil {
+						fnErr = err
+					}
+					mu.Unlock()
+				}
+			}(user)
+		}
+		return nil
+	})
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// checkMailboxFanOut returns an error if a per-user Gmail table, queried without a user, can't
+// query the mailbox of every user in the domain: either fan-out is disabled, or the connection's
+// credentials can't impersonate users, in which case querying each mailbox would fail.
+func checkMailboxFanOut(d *plugin.QueryData, qualName string) error {
+	if !userFanOutEnabled(d) {
+		return userFanOutDisabledError(qualName)
+	}
+
+	canImpersonate, err := canImpersonateUsers(d)
+	if err != nil {
+		return err
+	}
+	if !canImpersonate {
+		return fmt.Errorf("%s must be specified in the where clause, since enable_user_fan_out requires the credentials of a service account with domain-wide delegation", qualName)
+	}
+
+	return nil
+}
+
+// Returns true if the connection's credentials can impersonate the users of the domain, i.e. a
+// service account, or workload identity federation credentials impersonating a service account
+func canImpersonateUsers(d *plugin.QueryData) (bool, error) {
+	config := GetConfig(d.Connection)
+	if config.ImpersonateServiceAccount != nil && *config.ImpersonateServiceAccount != "" {
+		return true, nil
+	}
+
+	var credentials string
+	if config.Credentials != nil {
+		credentials = *config.Credentials
+	} else if config.CredentialFile != nil {
+		credentials = *config.CredentialFile
+	}
+	if credentials == "" {
+		return false, nil
+	}
+
+	content, err := pathOrContents(credentials)
+	if err != nil {
+		return false, err
+	}
+	return credentialsType(content) != "external_account", nil
+}
+
+// forEachMailbox calls fn for every active user in the domain with a mailbox, using a Gmail
+// service which impersonates that user. An error querying a user's mailbox doesn't fail the
+// query; instead, the row built by errorRow is streamed for that user.
+func forEachMailbox(ctx context.Context, d *plugin.QueryData, fn func(service *gmail.Service, userEmail string) error, errorRow func(userEmail string, err error) interface{}) error {
+	return forEachDomainUser(ctx, d, "isSuspended=false", userFanOutConcurrency(d), func(user *admin.User) error {
+		if !user.IsMailboxSetup {
+			return nil
+		}
+
+		service, err := GmailServiceForUser(ctx, d, user.PrimaryEmail)
+		if err == nil {
+			err = fn(service, user.PrimaryEmail)
+		}
+		if err != nil {
+			d.StreamListItem(ctx, errorRow(user.PrimaryEmail, err))
+		}
+
+		return nil
+	})
+}
+
+// gmailServiceForMailbox returns the Gmail service to query the given user's mailbox with.
+// If the user was specified in the where clause, the connection's own credentials are used;
+// otherwise the rows were fanned out across the domain, and the user is impersonated.
+func gmailServiceForMailbox(ctx context.Context, d *plugin.QueryData, qualName string, userEmail string) (*gmail.Service, error) {
+	if d.KeyColumnQuals[qualName] != nil {
+		return GmailService(ctx, d)
+	}
+	return GmailServiceForUser(ctx, d, userEmail)
+}
+
+// mailboxFanOut is shared by a row of a mailbox queried while fanning out across the domain, and
+// the hydrate calls of that row. An error querying the mailbox is recorded in it, to be returned in
+// the fan_out_error column, instead of failing the query. It is nil for the rows of a given user.
+type mailboxFanOut struct {
+	// The error listing the mailbox, if the row was streamed in place of the mailbox's rows
+	listErr error
+
+	mu         sync.Mutex
+	hydrateErr error
+}
+
+// Returns the mailboxFanOut of a new row, which is nil if the user was specified in the where clause
+func newMailboxFanOut(d *plugin.QueryData, qualName string) *mailboxFanOut {
+	if d.KeyColumnQuals[qualName] != nil {
+		return nil
+	}
+	return &mailboxFanOut{}
+}
+
+// Returns the mailboxFanOut of the row streamed for a mailbox which couldn't be listed
+func failedMailboxFanOut(err error) *mailboxFanOut {
+	return &mailboxFanOut{listErr: err}
+}
+
+// Returns true if the row was streamed for a mailbox which couldn't be listed
+func (f *mailboxFanOut) failed() bool {
+	return f != nil && f.listErr != nil
+}
+
+// hydrateError returns the error a hydrate call of the row should fail with. While fanning out,
+// the error is recorded in the row instead, and nil is returned.
+func (f *mailboxFanOut) hydrateError(err error) error {
+	if f == nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.hydrateErr == nil {
+		f.hydrateErr = err
+	}
+	return nil
+}
+
+// Returns the error recorded for the row, if any
+func (f *mailboxFanOut) err() error {
+	if f.listErr != nil {
+		return f.listErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hydrateErr
+}
+
+//// TRANSFORM FUNCTIONS
+
+// Returns the message of the error recorded in the row's mailboxFanOut, if any
+func fanOutErrorMessage(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	f, ok := d.Value.(*mailboxFanOut)
+	if !ok || f == nil || f.err() == nil {
+		return nil, nil
+	}
+	return f.err().Error(), nil
+}
+
+// Returns the error raised when a per-user table is queried without a user, and fan-out is disabled
+func userFanOutDisabledError(qualName string) error {
+	return fmt.Errorf("%s must be specified in the where clause, unless enable_user_fan_out is set in the connection config", qualName)
+}
@@ -1,11 +1,24 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/turbot/steampipe-plugin-googleworkspace/googleworkspace"
 	"github.com/turbot/steampipe-plugin-sdk/v3/plugin"
 )
 
 func main() {
+	// The login subcommand creates the token file used by the token_path connection config argument
+	if len(os.Args) > 1 && os.Args[1] == "login" {
+		if err := googleworkspace.Login(context.Background(), os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		PluginFunc: googleworkspace.Plugin})
 }